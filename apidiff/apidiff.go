@@ -0,0 +1,87 @@
+package apidiff
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// File is a changed file before and after the change. A nil side means the
+// file did not exist there.
+type File struct {
+	Path   string
+	Before []byte
+	After  []byte
+}
+
+// Change is an incompatible change to the exported API.
+type Change struct {
+	// Where is the Go package directory or the TypeScript file.
+	Where string
+	// Description says what changed, e.g. "removed func Parse".
+	Description string
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %s", c.Where, c.Description)
+}
+
+// Detect compares the exported declarations of the Go and TypeScript files
+// before and after the change. Go files are compared per package directory,
+// so declarations moved between files of a package are not reported as long
+// as files holds every file of the package, changed or not. Files that
+// cannot be parsed are skipped.
+func Detect(files []File) []Change {
+	var changes []Change
+
+	packages := make(map[string][]File)
+	var dirs []string
+
+	for _, file := range files {
+		switch {
+		case isGoSource(file.Path):
+			dir := filepath.Dir(file.Path)
+			if packages[dir] == nil {
+				dirs = append(dirs, dir)
+			}
+			packages[dir] = append(packages[dir], file)
+		case isTypeScriptSource(file.Path):
+			changes = append(changes, compareTypeScript(file)...)
+		}
+	}
+
+	slices.Sort(dirs)
+	for _, dir := range dirs {
+		changes = append(changes, compareGoPackage(dir, packages[dir])...)
+	}
+
+	return changes
+}
+
+// Supported reports whether Detect compares path, so callers only need to
+// read the files that matter.
+func Supported(path string) bool {
+	return isGoSource(path) || isTypeScriptSource(path)
+}
+
+func isGoSource(path string) bool {
+	if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+		return false
+	}
+
+	// internal packages and vendored code are not part of the public API
+	parts := strings.Split(filepath.ToSlash(filepath.Dir(path)), "/")
+	return !slices.Contains(parts, "internal") && !slices.Contains(parts, "vendor") && !slices.Contains(parts, "testdata")
+}
+
+func isTypeScriptSource(path string) bool {
+	ext := filepath.Ext(path)
+	if !slices.Contains([]string{".ts", ".tsx", ".mts", ".cts"}, ext) {
+		return false
+	}
+
+	base := filepath.Base(path)
+	return !strings.Contains(base, ".test.") && !strings.Contains(base, ".spec.") &&
+		!slices.Contains(strings.Split(filepath.ToSlash(path), "/"), "node_modules")
+}
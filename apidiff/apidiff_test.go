@@ -0,0 +1,133 @@
+package apidiff
+
+import (
+	"slices"
+	"testing"
+)
+
+func descriptions(changes []Change) []string {
+	var result []string
+	for _, change := range changes {
+		result = append(result, change.String())
+	}
+	return result
+}
+
+func TestDetectGo(t *testing.T) {
+	before := `package store
+
+type Config struct {
+	Path    string
+	Timeout int
+	cache   bool
+}
+
+type Reader interface {
+	Read(key string) ([]byte, error)
+}
+
+func Open(path string) (*Config, error) { return nil, nil }
+func Close(c *Config)                    {}
+func (c *Config) Keys() []string         { return nil }
+func helper()                            {}
+`
+	after := `package store
+
+type Config struct {
+	Path  string
+	Retry int
+}
+
+type Reader interface {
+	Read(key string) ([]byte, error)
+	Size() int
+}
+
+func Open(path string, readOnly bool) (*Config, error) { return nil, nil }
+func (c *Config) Keys() []string                       { return nil }
+func renamed()                                         {}
+`
+
+	got := descriptions(Detect([]File{{Path: "store/store.go", Before: []byte(before), After: []byte(after)}}))
+	want := []string{
+		"store: removed func Close",
+		"store: removed field Config.Timeout",
+		"store: changed func Open from func(string) (*Config, error) to func(string, bool) (*Config, error)",
+		"store: added method Reader.Size to interface Reader",
+	}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("Detect() =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestDetectGoIgnoresMovesAndPrivateCode(t *testing.T) {
+	files := []File{
+		// Open moves from a.go to b.go, parameter names change
+		{Path: "pkg/a.go", Before: []byte("package pkg\nfunc Open(path string) error { return nil }\n"), After: []byte("package pkg\n")},
+		{Path: "pkg/b.go", Before: []byte("package pkg\n"), After: []byte("package pkg\nfunc Open(p string) error { return nil }\n")},
+		{Path: "internal/x/x.go", Before: []byte("package x\nfunc Gone() {}\n"), After: []byte("package x\n")},
+		{Path: "cmd/main.go", Before: []byte("package main\nfunc Gone() {}\n"), After: []byte("package main\n")},
+		{Path: "pkg/a_test.go", Before: []byte("package pkg\nfunc TestGone() {}\n"), After: nil},
+	}
+
+	if got := Detect(files); len(got) != 0 {
+		t.Errorf("Detect() = %q, want no changes", descriptions(got))
+	}
+}
+
+func TestDetectTypeScript(t *testing.T) {
+	before := `export function createClient(url: string): Client {}
+export async function fetchAll(client: Client, limit = 10) {}
+export interface Options { retries: number }
+export const VERSION = "1"
+function local() {}
+export { local as helper }
+`
+	after := `// createClient now needs options
+export function createClient(url: string, options: Options): Client {}
+export async function fetchAll(client: Client,
+  limit = 10) {}
+export type Options = { retries: number }
+`
+
+	got := descriptions(Detect([]File{{Path: "src/client.ts", Before: []byte(before), After: []byte(after)}}))
+	want := []string{
+		"src/client.ts: removed export VERSION",
+		"src/client.ts: changed function createClient from (url: string): Client to (url: string, options: Options): Client",
+		"src/client.ts: removed export helper",
+		"src/client.ts: changed export Options from interface to type",
+	}
+
+	slices.Sort(got)
+	slices.Sort(want)
+	if !slices.Equal(got, want) {
+		t.Errorf("Detect() =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestDetectTypeScriptCompatible(t *testing.T) {
+	before := `const base = "https://api.example.com" // the public API
+export function fetchUser(id: string): Promise<User> {}
+export function search(query: string, limit?: number) {}
+export function format(value: number, cb: (a: number) => string) {}
+export function connect(url: string) {}
+`
+	after := `const base = "https://api.example.com" /* the public API */
+export function fetchUser(userId: string, options?: { cache: boolean, ttl: number }): Promise<User> {}
+export function search(query: string, limit?: number, offset = 0, ...tags: string[]) {}
+export function format(value: number, cb: (a: number) => string, locale = "en") {}
+export function connect(url: string, timeout: number) {}
+`
+
+	got := descriptions(Detect([]File{{Path: "src/api.ts", Before: []byte(before), After: []byte(after)}}))
+	want := []string{"src/api.ts: changed function connect from (url: string) to (url: string, timeout: number)"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Detect() =\n%q\nwant\n%q", got, want)
+	}
+
+	stripped := stripTypeScriptComments("const url = 'http://x' // note\nconst t = `a // b` /* c */\n")
+	if stripped != "const url = 'http://x' \nconst t = `a // b` \n" {
+		t.Errorf("stripTypeScriptComments() = %q", stripped)
+	}
+}
@@ -0,0 +1,254 @@
+package apidiff
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+)
+
+// goDecl is one exported declaration: a top-level func, type, var or const,
+// a method, a struct field or an interface method. Signature is what callers
+// depend on, e.g. the parameter and result types of a func.
+type goDecl struct {
+	Kind      string
+	Signature string
+}
+
+// compareGoPackage reports removed and changed declarations of the package
+// in dir, made of files.
+func compareGoPackage(dir string, files []File) []Change {
+	before, beforeMain, ok := goPackageAPI(files, func(f File) []byte { return f.Before })
+	if !ok {
+		return nil
+	}
+	after, afterMain, ok := goPackageAPI(files, func(f File) []byte { return f.After })
+	if !ok || beforeMain || afterMain {
+		return nil
+	}
+
+	var descriptions []string
+	for _, name := range sortedKeys(before) {
+		old := before[name]
+		current, found := after[name]
+
+		switch {
+		case !found:
+			descriptions = append(descriptions, fmt.Sprintf("removed %s %s", old.Kind, name))
+		case current.Kind != old.Kind:
+			descriptions = append(descriptions, fmt.Sprintf("changed %s from %s to %s", name, old.Kind, current.Kind))
+		case current.Signature != old.Signature && old.Signature != "" && current.Signature != "":
+			descriptions = append(descriptions, fmt.Sprintf("changed %s %s from %s to %s", old.Kind, name, old.Signature, current.Signature))
+		}
+	}
+
+	// A new method breaks every implementation of an interface
+	for _, name := range sortedKeys(after) {
+		if _, found := before[name]; found || after[name].Kind != "interface method" {
+			continue
+		}
+		owner, _, _ := strings.Cut(name, ".")
+		if before[owner].Kind == "type" && before[owner].Signature == "interface" {
+			descriptions = append(descriptions, fmt.Sprintf("added method %s to interface %s", name, owner))
+		}
+	}
+
+	changes := make([]Change, len(descriptions))
+	for i, description := range descriptions {
+		changes[i] = Change{Where: dir, Description: description}
+	}
+	return changes
+}
+
+// goPackageAPI collects the exported declarations of one side of files. It
+// reports whether the package is main and fails when a file does not parse.
+func goPackageAPI(files []File, side func(File) []byte) (map[string]goDecl, bool, bool) {
+	api := make(map[string]goDecl)
+	isMain := false
+
+	for _, file := range files {
+		src := side(file)
+		if src == nil {
+			continue
+		}
+
+		parsed, err := parser.ParseFile(token.NewFileSet(), file.Path, src, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, false, false
+		}
+
+		isMain = isMain || parsed.Name.Name == "main"
+		collectGoDecls(parsed, api)
+	}
+
+	return api, isMain, true
+}
+
+func collectGoDecls(file *ast.File, api map[string]goDecl) {
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if !decl.Name.IsExported() {
+				continue
+			}
+			if decl.Recv == nil {
+				api[decl.Name.Name] = goDecl{Kind: "func", Signature: funcSignature(decl.Type)}
+				continue
+			}
+			if receiver := receiverName(decl.Recv); ast.IsExported(receiver) {
+				api[receiver+"."+decl.Name.Name] = goDecl{Kind: "method", Signature: funcSignature(decl.Type)}
+			}
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					collectGoType(spec, api)
+				case *ast.ValueSpec:
+					kind := "var"
+					if decl.Tok == token.CONST {
+						kind = "const"
+					}
+					signature := ""
+					if spec.Type != nil {
+						signature = types.ExprString(spec.Type)
+					}
+					for _, name := range spec.Names {
+						if name.IsExported() {
+							api[name.Name] = goDecl{Kind: kind, Signature: signature}
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func collectGoType(spec *ast.TypeSpec, api map[string]goDecl) {
+	if !spec.Name.IsExported() {
+		return
+	}
+	name := spec.Name.Name
+	params := ""
+	if spec.TypeParams != nil {
+		params = "[" + fieldList(spec.TypeParams, true) + "]"
+	}
+
+	switch t := spec.Type.(type) {
+	case *ast.StructType:
+		api[name] = goDecl{Kind: "type", Signature: "struct" + params}
+		for _, field := range t.Fields.List {
+			for _, fieldName := range fieldNames(field) {
+				if ast.IsExported(fieldName) {
+					api[name+"."+fieldName] = goDecl{Kind: "field", Signature: types.ExprString(field.Type)}
+				}
+			}
+		}
+	case *ast.InterfaceType:
+		api[name] = goDecl{Kind: "type", Signature: "interface" + params}
+		for _, method := range t.Methods.List {
+			if fn, ok := method.Type.(*ast.FuncType); ok {
+				for _, methodName := range method.Names {
+					api[name+"."+methodName.Name] = goDecl{Kind: "interface method", Signature: funcSignature(fn)}
+				}
+				continue
+			}
+			api[name+"."+types.ExprString(method.Type)] = goDecl{Kind: "embedded interface"}
+		}
+	default:
+		signature := types.ExprString(spec.Type) + params
+		if spec.Assign.IsValid() {
+			signature = "= " + signature
+		}
+		api[name] = goDecl{Kind: "type", Signature: signature}
+	}
+}
+
+// funcSignature renders t without parameter names, which callers do not
+// depend on.
+func funcSignature(t *ast.FuncType) string {
+	signature := "func"
+	if t.TypeParams != nil {
+		signature += "[" + fieldList(t.TypeParams, true) + "]"
+	}
+	signature += "(" + fieldList(t.Params, false) + ")"
+
+	if t.Results != nil && len(t.Results.List) > 0 {
+		results := fieldList(t.Results, false)
+		if len(t.Results.List) > 1 || len(t.Results.List[0].Names) > 1 {
+			results = "(" + results + ")"
+		}
+		signature += " " + results
+	}
+	return signature
+}
+
+func fieldList(fields *ast.FieldList, withNames bool) string {
+	var parts []string
+	for _, field := range fields.List {
+		typ := types.ExprString(field.Type)
+		if withNames {
+			names := fieldNames(field)
+			parts = append(parts, strings.Join(names, ", ")+" "+typ)
+			continue
+		}
+		for range max(len(field.Names), 1) {
+			parts = append(parts, typ)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// fieldNames returns the names of field, or the type name of an embedded
+// field.
+func fieldNames(field *ast.Field) []string {
+	if len(field.Names) == 0 {
+		name := types.ExprString(field.Type)
+		name = strings.TrimPrefix(name, "*")
+		if i := strings.LastIndex(name, "."); i >= 0 {
+			name = name[i+1:]
+		}
+		if i := strings.Index(name, "["); i >= 0 {
+			name = name[:i]
+		}
+		return []string{name}
+	}
+
+	names := make([]string, len(field.Names))
+	for i, name := range field.Names {
+		names[i] = name.Name
+	}
+	return names
+}
+
+func receiverName(recv *ast.FieldList) string {
+	if len(recv.List) == 0 {
+		return ""
+	}
+
+	expr := recv.List[0].Type
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	switch t := expr.(type) {
+	case *ast.IndexExpr:
+		expr = t.X
+	case *ast.IndexListExpr:
+		expr = t.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
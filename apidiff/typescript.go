@@ -0,0 +1,229 @@
+package apidiff
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// TypeScript is compared textually, without a parser: exported functions,
+// classes, interfaces, types, enums, namespaces and variables, and names in
+// export lists. Only removals, kind changes and function signature changes
+// that break existing callers are reported.
+var (
+	tsFunction    = regexp.MustCompile(`(?m)^export\s+(?:declare\s+)?(?:default\s+)?(?:async\s+)?function\s*\*?\s*([A-Za-z_$][\w$]*)\s*(<[^>(]*>)?\s*\(([^)]*)\)\s*(?::\s*([^{;\n]+))?`)
+	tsDeclaration = regexp.MustCompile(`(?m)^export\s+(?:declare\s+)?(?:default\s+)?(?:abstract\s+)?(class|interface|type|enum|const enum|const|let|var|namespace)\s+([A-Za-z_$][\w$]*)`)
+	tsExportList  = regexp.MustCompile(`(?m)^export\s+(?:type\s+)?\{([^}]*)\}`)
+	whitespace    = regexp.MustCompile(`\s+`)
+)
+
+// tsDecl is one exported TypeScript declaration. The signature parts are
+// only set for functions.
+type tsDecl struct {
+	Kind       string
+	TypeParams string
+	Params     []string
+	Returns    string
+}
+
+func (d tsDecl) signature() string {
+	signature := fmt.Sprintf("%s(%s)", d.TypeParams, strings.Join(d.Params, ", "))
+	if d.Returns != "" {
+		signature += ": " + d.Returns
+	}
+	return signature
+}
+
+func compareTypeScript(file File) []Change {
+	if file.Before == nil {
+		return nil
+	}
+
+	before := typeScriptAPI(string(file.Before))
+	after := typeScriptAPI(string(file.After))
+
+	var changes []Change
+	for _, name := range sortedKeys(before) {
+		old := before[name]
+		current, found := after[name]
+
+		var description string
+		switch {
+		case !found:
+			description = fmt.Sprintf("removed export %s", name)
+		case current.Kind != old.Kind && old.Kind != "export" && current.Kind != "export":
+			description = fmt.Sprintf("changed export %s from %s to %s", name, old.Kind, current.Kind)
+		case old.Kind == "function" && current.Kind == "function" && !compatibleFunction(old, current):
+			description = fmt.Sprintf("changed function %s from %s to %s", name, old.signature(), current.signature())
+		default:
+			continue
+		}
+
+		changes = append(changes, Change{Where: file.Path, Description: description})
+	}
+
+	return changes
+}
+
+// compatibleFunction reports whether every call to old still compiles
+// against current: the same type parameters and result, the same parameter
+// types, and any new parameters optional, defaulted or rest parameters.
+func compatibleFunction(old, current tsDecl) bool {
+	if old.TypeParams != current.TypeParams || old.Returns != current.Returns || len(current.Params) < len(old.Params) {
+		return false
+	}
+
+	for i, param := range current.Params {
+		if i >= len(old.Params) {
+			if !optionalParam(param) {
+				return false
+			}
+			continue
+		}
+		if paramType(param) != paramType(old.Params[i]) || optionalParam(old.Params[i]) && !optionalParam(param) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func typeScriptAPI(source string) map[string]tsDecl {
+	source = stripTypeScriptComments(source)
+	api := make(map[string]tsDecl)
+
+	for _, match := range tsFunction.FindAllStringSubmatch(source, -1) {
+		api[match[1]] = tsDecl{
+			Kind:       "function",
+			TypeParams: normalizeTypeScript(match[2]),
+			Params:     splitParams(normalizeTypeScript(match[3])),
+			Returns:    normalizeTypeScript(match[4]),
+		}
+	}
+
+	for _, match := range tsDeclaration.FindAllStringSubmatch(source, -1) {
+		api[match[2]] = tsDecl{Kind: match[1]}
+	}
+
+	for _, match := range tsExportList.FindAllStringSubmatch(source, -1) {
+		for _, item := range strings.Split(match[1], ",") {
+			fields := strings.Fields(item)
+			if len(fields) == 0 {
+				continue
+			}
+			// "a as b" exports b
+			name := fields[len(fields)-1]
+			if _, found := api[name]; !found {
+				api[name] = tsDecl{Kind: "export"}
+			}
+		}
+	}
+
+	return api
+}
+
+// stripTypeScriptComments removes line and block comments, leaving string
+// and template literals such as "https://example.com" intact.
+func stripTypeScriptComments(source string) string {
+	var b strings.Builder
+	var quote byte
+
+	for i := 0; i < len(source); i++ {
+		c := source[i]
+
+		switch {
+		case quote != 0:
+			b.WriteByte(c)
+			if c == '\\' && i+1 < len(source) {
+				i++
+				b.WriteByte(source[i])
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'' || c == '`':
+			quote = c
+			b.WriteByte(c)
+		case strings.HasPrefix(source[i:], "//"):
+			end := strings.IndexByte(source[i:], '\n')
+			if end < 0 {
+				return b.String()
+			}
+			i += end - 1
+		case strings.HasPrefix(source[i:], "/*"):
+			end := strings.Index(source[i+2:], "*/")
+			if end < 0 {
+				return b.String()
+			}
+			i += end + 3
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String()
+}
+
+// splitParams splits a parameter list at the commas that are not nested in
+// brackets, braces, parentheses or type arguments.
+func splitParams(params string) []string {
+	if params == "" {
+		return nil
+	}
+
+	var parts []string
+	for {
+		param, rest, found := cutTopLevel(params, ',')
+		parts = append(parts, strings.TrimSpace(param))
+		if !found {
+			return parts
+		}
+		params = rest
+	}
+}
+
+// paramType returns the declared type of param without its name and
+// default value, or the default value when there is no type.
+func paramType(param string) string {
+	name, rest, found := cutTopLevel(param, ':')
+	if !found {
+		return strings.TrimSpace(name)
+	}
+	typ, _, _ := cutTopLevel(rest, '=')
+	return strings.TrimSpace(typ)
+}
+
+// optionalParam reports whether callers may leave param out.
+func optionalParam(param string) bool {
+	if _, _, defaulted := cutTopLevel(param, '='); defaulted {
+		return true
+	}
+	name, _, _ := cutTopLevel(param, ':')
+	return strings.HasSuffix(strings.TrimSpace(name), "?") || strings.HasPrefix(param, "...")
+}
+
+// cutTopLevel is strings.Cut for the first sep that is not nested in
+// brackets, braces, parentheses or type arguments. The arrow of a function
+// type neither closes a bracket nor counts as "=".
+func cutTopLevel(text string, sep byte) (string, string, bool) {
+	depth := 0
+	for i := 0; i < len(text); i++ {
+		arrow := i+1 < len(text) && text[i] == '=' && text[i+1] == '>'
+		switch c := text[i]; {
+		case arrow:
+			i++
+		case c == '(' || c == '[' || c == '{' || c == '<':
+			depth++
+		case c == ')' || c == ']' || c == '}' || c == '>':
+			depth--
+		case c == sep && depth == 0:
+			return text[:i], text[i+1:], true
+		}
+	}
+	return text, "", false
+}
+
+func normalizeTypeScript(text string) string {
+	text = whitespace.ReplaceAllString(strings.TrimSpace(text), " ")
+	text = strings.TrimSuffix(text, ",")
+	return strings.ReplaceAll(text, ", )", ")")
+}
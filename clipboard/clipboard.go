@@ -0,0 +1,66 @@
+package clipboard
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	"github.com/aymanbagabas/go-osc52/v2"
+)
+
+// Method is how text reached the clipboard.
+type Method string
+
+const (
+	// System is the native clipboard: pbcopy, clip.exe, wl-copy on Wayland,
+	// xclip or xsel on X11.
+	System Method = "system clipboard"
+	// Terminal asks the terminal emulator to set the clipboard with an OSC 52
+	// escape sequence, which also works over SSH.
+	Terminal Method = "terminal clipboard (OSC 52)"
+)
+
+// Copy puts text on the clipboard. In SSH sessions without a forwarded
+// display, or when no clipboard utility is installed, it falls back to OSC 52
+// so the text lands on the machine in front of the user.
+func Copy(text string) (Method, error) {
+	if !isRemote() && !clipboard.Unsupported {
+		if err := clipboard.WriteAll(text); err == nil {
+			return System, nil
+		}
+	}
+
+	if err := copyOSC52(text); err != nil {
+		return "", fmt.Errorf("failed to copy to the clipboard: %w", err)
+	}
+	return Terminal, nil
+}
+
+func isRemote() bool {
+	ssh := os.Getenv("SSH_TTY") != "" || os.Getenv("SSH_CONNECTION") != ""
+	display := os.Getenv("DISPLAY") != "" || os.Getenv("WAYLAND_DISPLAY") != ""
+	return ssh && !display
+}
+
+// copyOSC52 writes the sequence to the controlling terminal, so it works
+// while stdout is piped, wrapped for tmux and screen when needed. Without a
+// terminal nothing could receive it, so that is an error.
+func copyOSC52(text string) error {
+	tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf("no clipboard utility and no terminal to send OSC 52 to")
+	}
+	defer tty.Close()
+
+	sequence := osc52.New(text)
+	switch {
+	case os.Getenv("TMUX") != "":
+		sequence = sequence.Tmux()
+	case strings.HasPrefix(os.Getenv("TERM"), "screen"):
+		sequence = sequence.Screen()
+	}
+
+	_, err = sequence.WriteTo(tty)
+	return err
+}
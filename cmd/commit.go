@@ -15,10 +15,39 @@ well-formatted commit messages using AI.
 
 This helps you keep a clean, consistent commit history with less effort.
 
+Your pre-commit and commit-msg hooks run as part of the commit and
+their output is shown; set "skipHooks": true in the config to bypass them.
+Arguments after -- are passed to git commit.
+
+With --amend, diny describes everything HEAD and the index change compared
+to HEAD's parent, shows the current message for comparison and amends HEAD.
+It refuses when HEAD is already on its upstream unless --allow-pushed is set.
+
+With -a (--all), every change to tracked files is described and committed in
+one step, like git commit -a.
+
+With -p (--patch), diny first lists the staged and unstaged hunks and the
+untracked files so you can toggle exactly what goes into the commit; the
+message is generated once the selection is final.
+
+With --candidates N, diny generates N messages at once and lets you pick
+one, or combine their ideas with your feedback.
+
+With --yes the generated message is committed without the menu. When stdin
+or stdout is not a terminal, or with --no-interactive, there is no menu
+either: the message is printed to stdout, or committed with --yes.
+
 Examples:
   diny commit
   diny commit --lang hr
-  diny commit --style conventional`,
+  diny commit --style conventional
+  diny commit -- -S --signoff --author="Ada <ada@example.com>"
+  diny commit -a
+  diny commit -p
+  diny commit --amend
+  diny commit --candidates 3
+  diny commit --yes
+  diny commit --no-interactive > message.txt`,
 	Run: func(cmd *cobra.Command, args []string) {
 		commit.Main(cmd, args)
 	},
@@ -26,4 +55,10 @@ Examples:
 
 func init() {
 	rootCmd.AddCommand(commitCmd)
+	commitCmd.Flags().BoolP("all", "a", false, "Stage every change to tracked files and commit it, like git commit -a")
+	commitCmd.Flags().BoolP("patch", "p", false, "Choose the hunks to commit before generating the message")
+	commitCmd.Flags().Bool("amend", false, "Regenerate the message for HEAD and amend it")
+	commitCmd.Flags().Int("candidates", 1, "Generate N messages concurrently and pick one")
+	commitCmd.Flags().Bool("allow-pushed", false, "Allow --amend when HEAD has already been pushed")
+	commitCmd.Flags().BoolP("yes", "y", false, "Commit the generated message without showing the menu")
 }
@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/dinoDanic/diny/commit"
+	"github.com/dinoDanic/diny/config"
+	"github.com/dinoDanic/diny/history"
+	"github.com/dinoDanic/diny/ui"
+	"github.com/spf13/cobra"
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Browse and reuse previously generated commit messages",
+	Long: `Browse the messages diny generated in this repository.
+
+Every diny commit and diny message run is recorded in .git/diny-history.jsonl:
+the diff hash, the configuration, every generated candidate, your feedback
+and the message that was finally used. Sessions are numbered from the most
+recent one, which is 1.
+
+Examples:
+  diny history
+  diny history search "rate limit"
+  diny history show 3
+  diny history reuse 3 --candidate 2
+  diny history stats`,
+	Run: func(cmd *cobra.Command, args []string) {
+		limit, _ := cmd.Flags().GetInt("limit")
+		listSessions(loadHistoryOrExit(), limit)
+	},
+}
+
+var historySearchCmd = &cobra.Command{
+	Use:   "search <text>",
+	Short: "Find sessions whose messages or feedback contain text",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		query := strings.Join(args, " ")
+		sessions := loadHistoryOrExit()
+
+		var lines []string
+		for i, session := range sessions {
+			if session.Matches(query) {
+				lines = append(lines, sessionLine(i+1, session))
+			}
+		}
+
+		if len(lines) == 0 {
+			ui.RenderWarning(fmt.Sprintf("No sessions matching %q", query))
+			return
+		}
+
+		ui.RenderBox(fmt.Sprintf("Sessions matching %q", query), strings.Join(lines, "\n"))
+	},
+}
+
+var historyShowCmd = &cobra.Command{
+	Use:   "show <n>",
+	Short: "Show every candidate and the feedback of a session",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		session := sessionOrExit(args[0])
+
+		summary := fmt.Sprintf("%s  diny %s  %s", session.Time.Format("2006-01-02 15:04"), session.Command, session.Outcome)
+		if session.Commit != "" {
+			summary += fmt.Sprintf("\ncommit %s", session.Commit)
+		}
+		summary += fmt.Sprintf("\ndiff %s", shortHash(session.DiffHash))
+		ui.RenderBox("Session "+args[0], summary)
+
+		for i, candidate := range session.Candidates {
+			ui.RenderBox(fmt.Sprintf("Candidate %d", i+1), candidate)
+		}
+
+		if len(session.Feedback) > 0 {
+			ui.RenderBox("Feedback", "• "+strings.Join(session.Feedback, "\n• "))
+		}
+
+		if session.Edited {
+			ui.RenderBox("Used (edited)", session.Message)
+		}
+	},
+}
+
+var historyReuseCmd = &cobra.Command{
+	Use:   "reuse <n>",
+	Short: "Commit the staged changes with a message from a session",
+	Long: `Reuse the message that was used in a session, or one of its candidates
+with --candidate, for the currently staged changes. The usual commit menu
+lets you edit, regenerate or refine it first. With --print, or without a
+terminal, the message is written to stdout instead.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		session := sessionOrExit(args[0])
+
+		message := session.LastMessage()
+		if n, _ := cmd.Flags().GetInt("candidate"); n != 0 {
+			if n < 1 || n > len(session.Candidates) {
+				ui.RenderError(fmt.Sprintf("Session %s has %d candidates", args[0], len(session.Candidates)))
+				os.Exit(1)
+			}
+			message = session.Candidates[n-1]
+		}
+
+		if message == "" {
+			ui.RenderError(fmt.Sprintf("Session %s has no message", args[0]))
+			os.Exit(1)
+		}
+
+		if print, _ := cmd.Flags().GetBool("print"); print || !ui.IsInteractive() {
+			fmt.Print(message)
+			return
+		}
+
+		gitDiff, err := commit.GetStagedDiff()
+		if err != nil {
+			ui.RenderError(fmt.Sprintf("Failed to get git diff: %v", err))
+			os.Exit(1)
+		}
+
+		if len(gitDiff) == 0 {
+			ui.RenderWarning("No staged changes found. Stage files first with `git add`.")
+			os.Exit(0)
+		}
+
+		diff := string(gitDiff)
+		userConfig, _ := config.Load()
+
+		opts := commit.Options{Session: history.NewSession("commit", diff, userConfig)}
+		opts.Session.AddCandidates(message)
+
+		commit.HandleCommitFlow(message, diff, userConfig, opts)
+	},
+}
+
+var historyStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show how often generated messages were accepted, edited or regenerated",
+	Run: func(cmd *cobra.Command, args []string) {
+		sessions := loadHistoryOrExit()
+		if len(sessions) == 0 {
+			ui.RenderWarning("No history yet. Generate a message with diny commit first.")
+			return
+		}
+
+		ui.RenderBox("diny history stats", history.ComputeStats(sessions).String())
+	},
+}
+
+func listSessions(sessions []history.Session, limit int) {
+	if len(sessions) == 0 {
+		ui.RenderWarning("No history yet. Generate a message with diny commit first.")
+		return
+	}
+
+	if limit > 0 && len(sessions) > limit {
+		sessions = sessions[:limit]
+	}
+
+	lines := make([]string, len(sessions))
+	for i, session := range sessions {
+		lines[i] = sessionLine(i+1, session)
+	}
+
+	ui.RenderBox("History", strings.Join(lines, "\n"))
+}
+
+func sessionLine(n int, session history.Session) string {
+	edited := ""
+	if session.Edited {
+		edited = " (edited)"
+	}
+
+	return fmt.Sprintf("%3d  %s  %-9s  %s%s", n, session.Time.Format("2006-01-02 15:04"), session.Outcome, session.Subject(), edited)
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
+
+func loadHistoryOrExit() []history.Session {
+	sessions, err := history.Load()
+	if err != nil {
+		ui.RenderError(fmt.Sprintf("Failed to load history: %v", err))
+		os.Exit(1)
+	}
+	return sessions
+}
+
+func sessionOrExit(arg string) history.Session {
+	sessions := loadHistoryOrExit()
+
+	n, err := strconv.Atoi(arg)
+	if err != nil || n < 1 || n > len(sessions) {
+		ui.RenderError(fmt.Sprintf("No session %s, see diny history for the list", arg))
+		os.Exit(1)
+	}
+
+	return sessions[n-1]
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+	historyCmd.AddCommand(historySearchCmd)
+	historyCmd.AddCommand(historyShowCmd)
+	historyCmd.AddCommand(historyReuseCmd)
+	historyCmd.AddCommand(historyStatsCmd)
+
+	historyCmd.Flags().Int("limit", 20, "Number of sessions to list, 0 for all")
+	historyReuseCmd.Flags().Int("candidate", 0, "Reuse candidate N of the session instead of the message that was used")
+	historyReuseCmd.Flags().Bool("print", false, "Print the message to stdout instead of committing")
+}
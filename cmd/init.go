@@ -6,9 +6,11 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"slices"
 
 	"github.com/charmbracelet/huh"
 	"github.com/dinoDanic/diny/config"
+	"github.com/dinoDanic/diny/git"
 	"github.com/dinoDanic/diny/ui"
 	"github.com/spf13/cobra"
 )
@@ -16,13 +18,12 @@ import (
 // RunConfigurationSetup runs the interactive configuration setup and returns the config
 func RunConfigurationSetup() config.UserConfig {
 	// Start with default configuration values
-	userConfig := config.UserConfig{
-		UseEmoji:        false,
-		UseConventional: false,
-		Tone:            config.Casual,
-		Length:          config.Short,
-	}
+	return RunConfigurationSetupWith(config.Default())
+}
 
+// RunConfigurationSetupWith runs the interactive configuration setup with
+// userConfig as the proposed answers
+func RunConfigurationSetupWith(userConfig config.UserConfig) config.UserConfig {
 	// Emoji confirmation
 	err := huh.NewConfirm().
 		Title("Use emoji prefixes in commit messages?").
@@ -108,18 +109,56 @@ Configuration includes:
 - Tone: Professional, casual, or friendly
 - Length: Short, normal, or detailed messages
 
-Git hooks will automatically populate commit messages using diny when you run 'git commit'.`,
+Git hooks will automatically populate commit messages using diny when you run 'git commit'.
+
+Every answer can be given as a flag. Without a terminal, or with
+--no-interactive, --yes is required. With --yes nothing is asked: the
+configuration is saved from flags and defaults, and hooks are installed
+when --hooks is set. --from-history analyzes the last N commits and
+proposes settings that match the existing style.
+
+Examples:
+  diny init
+  diny init --from-history
+  diny init --yes --conventional --emoji=false --tone professional --hooks
+  diny init --yes --from-history=200`,
 	Run: func(cmd *cobra.Command, args []string) {
-		var selectedOption InitOption
+		userConfig, err := initialConfig(cmd)
+		if err != nil {
+			ui.RenderError(err.Error())
+			os.Exit(1)
+		}
+
+		hooks, _ := cmd.Flags().GetBool("hooks")
+		yes, _ := cmd.Flags().GetBool("yes")
+
+		if !yes && !ui.IsInteractive() {
+			ui.RenderError("diny init asks questions and needs a terminal. Pass --yes to save the configuration from flags and defaults.")
+			os.Exit(1)
+		}
+
+		if yes {
+			saveConfiguration(userConfig)
+			if hooks {
+				runHookInstallation()
+			}
+			return
+		}
+
+		selected := []InitOption{ConfigureProject}
+		if hooks {
+			selected = append(selected, InstallHooks)
+		}
 
 		// Ask user what they want to initialize
-		err := huh.NewSelect[InitOption]().
+		err = huh.NewMultiSelect[InitOption]().
 			Title("What would you like to initialize?").
+			Description("Toggle with space or x, confirm with Enter").
 			Options(
 				huh.NewOption("Configure project settings", ConfigureProject),
 				huh.NewOption("Install git hooks", InstallHooks),
 			).
-			Value(&selectedOption).
+			Value(&selected).
 			Run()
 
 		if err != nil {
@@ -128,19 +167,71 @@ Git hooks will automatically populate commit messages using diny when you run 'g
 		}
 
 		// Execute based on selection
-		switch selectedOption {
-		case ConfigureProject:
-			runConfigurationSetup()
-		case InstallHooks:
-			runHookInstallation()
+		for _, option := range selected {
+			switch option {
+			case ConfigureProject:
+				runConfigurationSetup(userConfig)
+			case InstallHooks:
+				runHookInstallation()
+			}
 		}
 	},
 }
 
-func runConfigurationSetup() {
+// initialConfig builds the proposed configuration from the current
+// configuration (or defaults), the analyzed history and explicit flags, in
+// that order of precedence.
+func initialConfig(cmd *cobra.Command) (config.UserConfig, error) {
+	userConfig := config.Default()
+	if existing, err := config.Load(); err == nil && existing != nil {
+		userConfig = *existing
+	}
+	flags := cmd.Flags()
+
+	if n, _ := flags.GetInt("from-history"); n > 0 {
+		messages, err := git.GetRecentCommitMessages(n)
+		if err != nil {
+			return userConfig, err
+		}
+
+		style := config.DetectStyle(messages)
+		style.Apply(&userConfig)
+		ui.RenderBox("Commit history", style.String())
+	}
+
+	if flags.Changed("conventional") {
+		userConfig.UseConventional, _ = flags.GetBool("conventional")
+	}
+
+	if flags.Changed("emoji") {
+		userConfig.UseEmoji, _ = flags.GetBool("emoji")
+	}
+
+	if flags.Changed("tone") {
+		tone, _ := flags.GetString("tone")
+		userConfig.Tone = config.Tone(tone)
+		if !slices.Contains([]config.Tone{config.Professional, config.Casual, config.Friendly}, userConfig.Tone) {
+			return userConfig, fmt.Errorf("invalid --tone %q, use professional, casual or friendly", tone)
+		}
+	}
+
+	if flags.Changed("length") {
+		length, _ := flags.GetString("length")
+		userConfig.Length = config.Length(length)
+		if !slices.Contains([]config.Length{config.Short, config.Normal, config.Long}, userConfig.Length) {
+			return userConfig, fmt.Errorf("invalid --length %q, use short, normal or long", length)
+		}
+	}
+
+	return userConfig, nil
+}
+
+func runConfigurationSetup(initial config.UserConfig) {
 	ui.RenderTitle("🔧 Configuration Setup")
-	userConfig := RunConfigurationSetup()
+	saveConfiguration(RunConfigurationSetupWith(initial))
+}
 
+func saveConfiguration(userConfig config.UserConfig) {
 	err := config.Save(userConfig)
 	if err != nil {
 		fmt.Printf("Error saving configuration: %v\n", err)
@@ -167,13 +258,12 @@ func runHookInstallation() {
 func init() {
 	rootCmd.AddCommand(initCmd)
 
-	// Here you will define your flags and configuration settings.
-
-	// Cobra supports Persistent Flags which will work for this command
-	// and all subcommands, e.g.:
-	// initCmd.PersistentFlags().String("foo", "", "A help for foo")
-
-	// Cobra supports local flags which will only run when this command
-	// is called directly, e.g.:
-	// initCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	initCmd.Flags().BoolP("yes", "y", false, "Do not ask anything; use flags and defaults")
+	initCmd.Flags().Bool("conventional", false, "Use Conventional Commits format")
+	initCmd.Flags().Bool("emoji", false, "Use emoji in commit messages")
+	initCmd.Flags().String("tone", "", "Commit message tone: professional, casual or friendly")
+	initCmd.Flags().String("length", "", "Commit message length: short, normal or long")
+	initCmd.Flags().Bool("hooks", false, "Install the git hook")
+	initCmd.Flags().Int("from-history", 0, "Propose settings from the last N commits (default 50 when set without a value)")
+	initCmd.Flags().Lookup("from-history").NoOptDefVal = "50"
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
@@ -19,33 +20,72 @@ This hook will:
 - Write it to .git/COMMIT_EDITMSG as a draft
 - Allow you to edit the message in your editor
 
-The hook only runs for regular commits, not for merges, rebases, or squashes.`,
+A post-commit hook is installed as well so diny can learn from the edits
+you make to its messages (see 'diny learn').
+
+The hook only runs for regular commits, not for merges, rebases, or squashes.
+
+With --commit-msg, a commit-msg hook is installed as well. It runs
+'diny lint' on every commit message, including hand-written ones, and
+rejects the commit when the message breaks your diny configuration.
+Existing post-commit and commit-msg hooks that diny did not write are
+kept as they are.
+
+Examples:
+  diny install-hook
+  diny install-hook --commit-msg`,
 	Run: func(cmd *cobra.Command, args []string) {
+		commitMsg, _ := cmd.Flags().GetBool("commit-msg")
+
 		if err := installGitHook(); err != nil {
 			fmt.Fprintf(os.Stderr, "Error installing git hook: %v\n", err)
 			os.Exit(1)
 		}
 		fmt.Println("✅ Git hook installed successfully!")
 		fmt.Println("Now when you run 'git commit', diny will pre-populate your commit message.")
+
+		if commitMsg {
+			installed, err := installCommitMsgHook()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error installing commit-msg hook: %v\n", err)
+				os.Exit(1)
+			}
+			if !installed {
+				return
+			}
+			fmt.Println("✅ commit-msg hook installed successfully!")
+			fmt.Println("Commit messages will now be checked with 'diny lint'.")
+		}
 	},
 }
 
-func installGitHook() error {
+// prepareHooksDir makes sure .git/hooks exists and returns it together with
+// the path to the current diny binary.
+func prepareHooksDir() (string, string, error) {
 	// Check if we're in a git repository
 	if _, err := os.Stat(".git"); os.IsNotExist(err) {
-		return fmt.Errorf("not in a git repository")
+		return "", "", fmt.Errorf("not in a git repository")
 	}
 
 	// Create hooks directory if it doesn't exist
 	hooksDir := ".git/hooks"
 	if err := os.MkdirAll(hooksDir, 0755); err != nil {
-		return fmt.Errorf("failed to create hooks directory: %v", err)
+		return "", "", fmt.Errorf("failed to create hooks directory: %v", err)
 	}
 
 	// Get the path to the current diny binary
 	dinyPath, err := os.Executable()
 	if err != nil {
-		return fmt.Errorf("failed to get diny executable path: %v", err)
+		return "", "", fmt.Errorf("failed to get diny executable path: %v", err)
+	}
+
+	return hooksDir, dinyPath, nil
+}
+
+func installGitHook() error {
+	hooksDir, dinyPath, err := prepareHooksDir()
+	if err != nil {
+		return err
 	}
 
 	// Create the hook script
@@ -57,16 +97,21 @@ COMMIT_MSG_FILE=$1
 COMMIT_SOURCE=$2
 SHA1=$3
 
+# diny commit writes the message itself
+if [ -n "$DINY_COMMIT" ]; then
+    exit 0
+fi
+
 # Only run for regular commits (not merges, rebases, etc.)
 if [ -z "$COMMIT_SOURCE" ] || [ "$COMMIT_SOURCE" = "message" ]; then
-    # Check if there are staged changes
-    if ! git diff --cached --quiet; then
-        # Generate commit message using diny
-        if command -v %s >/dev/null 2>&1; then
-            DINY_MSG=$(%s message 2>/dev/null)
-            if [ $? -eq 0 ] && [ -n "$DINY_MSG" ]; then
-                # If the commit message file is empty or only has comments, replace it
-                if [ ! -s "$COMMIT_MSG_FILE" ] || ! grep -q '^[^#]' "$COMMIT_MSG_FILE"; then
+    # Only fill a message that is empty or only has comments
+    if [ ! -s "$COMMIT_MSG_FILE" ] || ! grep -q '^[^#]' "$COMMIT_MSG_FILE"; then
+        # Check if there are staged changes
+        if ! git diff --cached --quiet; then
+            # Generate commit message using diny
+            if command -v %s >/dev/null 2>&1; then
+                DINY_MSG=$(%s message --no-interactive 2>/dev/null)
+                if [ $? -eq 0 ] && [ -n "$DINY_MSG" ]; then
                     echo "$DINY_MSG" > "$COMMIT_MSG_FILE"
                     echo "" >> "$COMMIT_MSG_FILE"
                     echo "# Commit message generated by diny" >> "$COMMIT_MSG_FILE"
@@ -84,9 +129,73 @@ fi
 		return fmt.Errorf("failed to write hook script: %v", err)
 	}
 
+	return installPostCommitHook(hooksDir, dinyPath)
+}
+
+// installPostCommitHook lets diny learn from edits to the messages written by
+// the prepare-commit-msg hook. A post-commit hook that diny did not write is
+// left alone.
+func installPostCommitHook(hooksDir, dinyPath string) error {
+	hookPath := filepath.Join(hooksDir, "post-commit")
+	if existing, err := os.ReadFile(hookPath); err == nil && !strings.Contains(string(existing), "generated by diny") {
+		fmt.Fprintf(os.Stderr, "Keeping your existing post-commit hook. Add '%s learn capture --no-interactive' to it to let diny learn from your edits.\n", dinyPath)
+		return nil
+	}
+
+	hookScript := fmt.Sprintf(`#!/bin/sh
+# post-commit hook generated by diny
+# This hook lets diny learn from your edits to generated commit messages
+
+# diny commit records its own edits
+if [ -n "$DINY_COMMIT" ]; then
+    exit 0
+fi
+
+if command -v %s >/dev/null 2>&1; then
+    %s learn capture --no-interactive >/dev/null 2>&1 || true
+fi
+`, dinyPath, dinyPath)
+
+	if err := os.WriteFile(hookPath, []byte(hookScript), 0755); err != nil {
+		return fmt.Errorf("failed to write hook script: %v", err)
+	}
+
 	return nil
 }
 
+// installCommitMsgHook installs the lint hook and reports whether it did. A
+// commit-msg hook that diny did not write is left alone.
+func installCommitMsgHook() (bool, error) {
+	hooksDir, dinyPath, err := prepareHooksDir()
+	if err != nil {
+		return false, err
+	}
+
+	hookPath := filepath.Join(hooksDir, "commit-msg")
+	if existing, err := os.ReadFile(hookPath); err == nil && !strings.Contains(string(existing), "generated by diny") {
+		fmt.Fprintf(os.Stderr, "Keeping your existing commit-msg hook. Add '%s lint --no-interactive \"$1\" || exit 1' to it to check messages with diny.\n", dinyPath)
+		return false, nil
+	}
+
+	hookScript := fmt.Sprintf(`#!/bin/sh
+# commit-msg hook generated by diny
+# This hook checks commit messages against your diny configuration
+
+COMMIT_MSG_FILE=$1
+
+if command -v %s >/dev/null 2>&1; then
+    exec %s lint --no-interactive "$COMMIT_MSG_FILE"
+fi
+`, dinyPath, dinyPath)
+
+	if err := os.WriteFile(hookPath, []byte(hookScript), 0755); err != nil {
+		return false, fmt.Errorf("failed to write hook script: %v", err)
+	}
+
+	return true, nil
+}
+
 func init() {
 	rootCmd.AddCommand(installHookCmd)
-}
\ No newline at end of file
+	installHookCmd.Flags().Bool("commit-msg", false, "Also install a commit-msg hook that runs diny lint")
+}
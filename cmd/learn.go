@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/huh"
+	"github.com/dinoDanic/diny/commit"
+	"github.com/dinoDanic/diny/git"
+	"github.com/dinoDanic/diny/history"
+	"github.com/dinoDanic/diny/learn"
+	"github.com/dinoDanic/diny/ui"
+	"github.com/spf13/cobra"
+)
+
+var learnCmd = &cobra.Command{
+	Use:   "learn",
+	Short: "Review what diny learned from your edits to its messages",
+	Long: `Review what diny learned from your edits to generated messages.
+
+Whenever you edit or refine a diny message before committing, in diny commit
+or in the message written by the git hook, diny stores the generated and the
+final message in .git/diny-corrections.jsonl. The most frequent lessons, such
+as "Do not start the subject with "Update"" or "Use scope "ui"", are sent
+as guidance with later requests in this repository.
+
+Examples:
+  diny learn
+  diny learn list
+  diny learn clear`,
+	Run: func(cmd *cobra.Command, args []string) {
+		corrections := loadCorrectionsOrExit()
+		if len(corrections) == 0 {
+			ui.RenderWarning("Nothing learned yet. Edit or refine a generated message and diny will pick it up.")
+			return
+		}
+
+		guidance := learn.Guidance(corrections, learn.GuidanceCount)
+		ui.RenderBox(fmt.Sprintf("Learned from %d corrections", len(corrections)), "• "+strings.Join(guidance, "\n• "))
+	},
+}
+
+var learnListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the recorded corrections",
+	Run: func(cmd *cobra.Command, args []string) {
+		corrections := loadCorrectionsOrExit()
+		if len(corrections) == 0 {
+			ui.RenderWarning("Nothing learned yet.")
+			return
+		}
+
+		for i, correction := range corrections {
+			content := fmt.Sprintf("Generated:\n%s\n\nCommitted:\n%s", correction.Generated, correction.Final)
+			if len(correction.Feedback) > 0 {
+				content += "\n\nFeedback:\n• " + strings.Join(correction.Feedback, "\n• ")
+			}
+			ui.RenderBox(fmt.Sprintf("%d. %s", i+1, correction.Time.Format("2006-01-02 15:04")), content)
+		}
+	},
+}
+
+var learnClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Forget everything diny learned in this repository",
+	Run: func(cmd *cobra.Command, args []string) {
+		yes, _ := cmd.Flags().GetBool("yes")
+
+		if !yes {
+			if !ui.IsInteractive() {
+				ui.RenderError("Refusing to clear learned data without --yes")
+				os.Exit(1)
+			}
+
+			err := huh.NewConfirm().
+				Title("Forget every recorded correction?").
+				Affirmative("Yes").
+				Negative("No").
+				Value(&yes).
+				Run()
+			if err != nil {
+				ui.RenderError(fmt.Sprintf("Error running prompt: %v", err))
+				os.Exit(1)
+			}
+		}
+
+		if !yes {
+			return
+		}
+
+		if err := learn.Clear(); err != nil {
+			ui.RenderError(err.Error())
+			os.Exit(1)
+		}
+
+		ui.RenderSuccess("Cleared learned data")
+	},
+}
+
+var learnCaptureCmd = &cobra.Command{
+	Use:    "capture",
+	Short:  "Record how the last commit changed the message generated by the git hook",
+	Hidden: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := captureHeadCorrection(); err != nil {
+			fmt.Fprintf(os.Stderr, "diny learn capture: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// captureHeadCorrection compares HEAD with the message diny printed, copied
+// or saved as a draft for the same changes, as the prepare-commit-msg hook
+// and GUI clients do.
+func captureHeadCorrection() error {
+	diff, err := commit.GetAmendDiff(false)
+	if err != nil {
+		return err
+	}
+	hash := history.HashDiff(string(diff))
+
+	sessions, err := history.Load()
+	if err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		if session.Outcome != history.Printed || session.DiffHash != hash {
+			continue
+		}
+
+		final, err := git.GetCommitMessage("HEAD")
+		if err != nil {
+			return err
+		}
+
+		if final == strings.TrimSpace(session.Message) {
+			return nil
+		}
+
+		head, err := git.GetHeadHash()
+		if err != nil {
+			return err
+		}
+
+		return learn.Record(learn.Correction{
+			Time:      time.Now(),
+			Commit:    head,
+			Generated: session.Message,
+			Final:     final,
+		})
+	}
+
+	return nil
+}
+
+func loadCorrectionsOrExit() []learn.Correction {
+	corrections, err := learn.Load()
+	if err != nil {
+		ui.RenderError(fmt.Sprintf("Failed to load learned data: %v", err))
+		os.Exit(1)
+	}
+	return corrections
+}
+
+func init() {
+	rootCmd.AddCommand(learnCmd)
+	learnCmd.AddCommand(learnListCmd)
+	learnCmd.AddCommand(learnClearCmd)
+	learnCmd.AddCommand(learnCaptureCmd)
+
+	learnClearCmd.Flags().BoolP("yes", "y", false, "Do not ask for confirmation")
+}
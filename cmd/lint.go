@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/dinoDanic/diny/commitlint"
+	"github.com/dinoDanic/diny/config"
+	"github.com/dinoDanic/diny/git"
+	"github.com/dinoDanic/diny/lint"
+	"github.com/spf13/cobra"
+)
+
+var lintCmd = &cobra.Command{
+	Use:   "lint <file|->",
+	Short: "Check a commit message against your diny configuration",
+	Long: `Check a commit message against the active diny configuration and
+the repository's commitlint rules.
+
+Checks include:
+- Conventional format and allowed types (when conventional commits are enabled)
+- Emoji policy
+- Header length and the blank line after it
+- Required trailers (Signed-off-by and configured trailers)
+
+Git comment lines are ignored. Merge, revert and fixup messages are accepted.
+Exits with status 1 when an error-level problem is found.
+
+Examples:
+  diny lint .git/COMMIT_EDITMSG
+  git log -1 --format=%B | diny lint -`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		message, err := readMessage(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to read commit message: %v\n", err)
+			os.Exit(1)
+		}
+
+		userConfig, _ := config.Load()
+
+		var lintConfig *commitlint.Config
+		if gitRoot, err := git.FindGitRoot(); err == nil {
+			lintConfig, err = commitlint.Load(gitRoot)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Ignoring commitlint config: %v\n", err)
+			}
+		}
+
+		violations := lint.Check(message, userConfig, lintConfig)
+		if len(violations) == 0 {
+			return
+		}
+
+		fmt.Fprintf(os.Stderr, "🦕 diny lint found problems with the commit message:\n\n%s\n\n", commitlint.Format(violations))
+
+		if commitlint.HasErrors(violations) {
+			os.Exit(1)
+		}
+	},
+}
+
+func readMessage(path string) (string, error) {
+	if path == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		return string(data), err
+	}
+
+	data, err := os.ReadFile(path)
+	return string(data), err
+}
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+}
@@ -1,11 +1,15 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 
+	"github.com/dinoDanic/diny/clipboard"
 	"github.com/dinoDanic/diny/commit"
+	"github.com/dinoDanic/diny/commitlint"
 	"github.com/dinoDanic/diny/config"
+	"github.com/dinoDanic/diny/history"
 	"github.com/spf13/cobra"
 )
 
@@ -16,12 +20,40 @@ var messageCmd = &cobra.Command{
 	Long: `Generate a commit message from staged changes and output to stdout.
 Designed for piping to other commands or scripts.
 
+--format json prints an object with the message, its header, subject, body,
+type, scope, breaking flag and footers (trailers included), the backend used,
+durationMs, the files of the diff and the breaking API changes found.
+
+--copy also puts the message on the clipboard, natively or with an OSC 52
+escape sequence over SSH, and --output writes it to a file instead of stdout.
+
+Other changes can be described as well: --all uses the working tree against
+HEAD (like git commit -a), --range A..B the changes between two revisions and
+--commit SHA a single commit. Paths after -- limit the diff to them.
+
 Examples:
   diny message | git commit -F -
-  diny message | pbcopy
-  diny message > commit.txt`,
+  diny message --copy
+  diny message --format json | jq -r .subject
+  diny message --output commit.txt
+  diny message --all
+  diny message -- src/api
+  diny message --range main..feature
+  diny message --commit HEAD~2`,
 	Run: func(cmd *cobra.Command, args []string) {
-		gitDiff, err := commit.GetStagedDiff()
+		outputFormat, _ := cmd.Flags().GetString("format")
+		if outputFormat != "text" && outputFormat != "json" {
+			fmt.Fprintf(os.Stderr, "Invalid --format %q, use text or json\n", outputFormat)
+			os.Exit(1)
+		}
+
+		var source commit.DiffSource
+		source.All, _ = cmd.Flags().GetBool("all")
+		source.Range, _ = cmd.Flags().GetString("range")
+		source.Commit, _ = cmd.Flags().GetString("commit")
+		source.Paths = args
+
+		gitDiff, err := commit.GetDiff(source)
 
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Failed to get git diff: %v\n", err)
@@ -29,23 +61,72 @@ Examples:
 		}
 
 		if len(gitDiff) == 0 {
-			fmt.Fprintf(os.Stderr, "No staged changes found. Stage files first with `git add`.\n")
+			if source.IsStaged() {
+				fmt.Fprintf(os.Stderr, "No staged changes found. Stage files first with `git add`.\n")
+			} else {
+				fmt.Fprintf(os.Stderr, "No changes found.\n")
+			}
 			os.Exit(0)
 		}
 
 		diff := string(gitDiff)
 		userConfig, err := config.Load()
 
-		commitMessage, err := commit.CreateCommitMessage(diff, userConfig)
+		message, err := commit.CreateCommitMessage(diff, commit.StyleExamples(diff, commit.StyleExampleCount), userConfig)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error generating commit message: %v\n", err)
 			os.Exit(1)
 		}
+		message = message.WithBreaking(commit.DetectBreaking(diff, source), userConfig)
+		commitMessage := message.Text
 
-		fmt.Print(commitMessage)
+		if violations := commit.LintCommitMessage(commitMessage); len(violations) > 0 {
+			fmt.Fprintf(os.Stderr, "commitlint found problems with this message:\n%s\n", commitlint.Format(violations))
+		}
+
+		session := history.NewSession("message", diff, userConfig)
+		session.AddCandidates(commitMessage)
+		session.Finish(history.Printed, commitMessage)
+
+		content := commitMessage
+		if outputFormat == "json" {
+			data, err := json.MarshalIndent(message, "", "  ")
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to encode the message: %v\n", err)
+				os.Exit(1)
+			}
+			content = string(data)
+		}
+
+		output, _ := cmd.Flags().GetString("output")
+		if output != "" {
+			if err := os.WriteFile(output, []byte(content+"\n"), 0644); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to write %s: %v\n", output, err)
+				os.Exit(1)
+			}
+		} else if outputFormat == "json" {
+			fmt.Println(content)
+		} else {
+			fmt.Print(content)
+		}
+
+		if copyMessage, _ := cmd.Flags().GetBool("copy"); copyMessage {
+			method, err := clipboard.Copy(commitMessage)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+				os.Exit(1)
+			}
+			fmt.Fprintf(os.Stderr, "\nCopied to the %s\n", method)
+		}
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(messageCmd)
+	messageCmd.Flags().BoolP("all", "a", false, "Describe every change to tracked files against HEAD")
+	messageCmd.Flags().String("range", "", "Describe the changes between two revisions, e.g. main..feature")
+	messageCmd.Flags().String("commit", "", "Describe the changes made by a single commit")
+	messageCmd.Flags().String("format", "text", "Output format: text or json")
+	messageCmd.Flags().Bool("copy", false, "Copy the message to the clipboard")
+	messageCmd.Flags().StringP("output", "o", "", "Write the message to a file instead of stdout")
 }
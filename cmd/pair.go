@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dinoDanic/diny/config"
+	"github.com/dinoDanic/diny/ui"
+	"github.com/spf13/cobra"
+)
+
+var pairCmd = &cobra.Command{
+	Use:   "pair",
+	Short: "Manage co-authors added to generated commit messages",
+	Long: `Manage the co-author roster for this repository.
+
+Every co-author in the roster is added to generated commit messages
+as a Co-authored-by trailer. The roster is stored in .git/diny-pairs.json.
+
+Examples:
+  diny pair add "Ada Lovelace <ada@example.com>"
+  diny pair remove ada@example.com
+  diny pair list`,
+	Run: func(cmd *cobra.Command, args []string) {
+		listPairs()
+	},
+}
+
+var pairAddCmd = &cobra.Command{
+	Use:   "add <Name <email>>",
+	Short: "Add a co-author to the roster",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		coAuthor := strings.TrimSpace(strings.Join(args, " "))
+		if err := config.ValidatePair(coAuthor); err != nil {
+			ui.RenderError(err.Error())
+			os.Exit(1)
+		}
+
+		pairs := loadPairsOrExit()
+		pairs, added := pairs.Add(coAuthor)
+		if !added {
+			ui.RenderWarning(fmt.Sprintf("%s is already in the roster", coAuthor))
+			return
+		}
+
+		savePairsOrExit(pairs)
+		ui.RenderSuccess(fmt.Sprintf("Added co-author %s", coAuthor))
+	},
+}
+
+var pairRemoveCmd = &cobra.Command{
+	Use:   "remove <name|email>",
+	Short: "Remove a co-author from the roster",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		query := strings.Join(args, " ")
+
+		pairs := loadPairsOrExit()
+		pairs, removed := pairs.Remove(query)
+		if !removed {
+			ui.RenderWarning(fmt.Sprintf("No co-author matching %q", query))
+			return
+		}
+
+		savePairsOrExit(pairs)
+		ui.RenderSuccess(fmt.Sprintf("Removed co-author %s", query))
+	},
+}
+
+var pairListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List co-authors in the roster",
+	Run: func(cmd *cobra.Command, args []string) {
+		listPairs()
+	},
+}
+
+func listPairs() {
+	pairs := loadPairsOrExit()
+	if len(pairs) == 0 {
+		ui.RenderWarning("No co-authors yet. Add one with: diny pair add \"Name <email>\"")
+		return
+	}
+
+	ui.RenderBox("Co-authors", "• "+strings.Join(pairs, "\n• "))
+}
+
+func loadPairsOrExit() config.Pairs {
+	pairs, err := config.LoadPairs()
+	if err != nil {
+		ui.RenderError(fmt.Sprintf("Failed to load co-authors: %v", err))
+		os.Exit(1)
+	}
+	return pairs
+}
+
+func savePairsOrExit(pairs config.Pairs) {
+	if err := config.SavePairs(pairs); err != nil {
+		ui.RenderError(fmt.Sprintf("Failed to save co-authors: %v", err))
+		os.Exit(1)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(pairCmd)
+	pairCmd.AddCommand(pairAddCmd)
+	pairCmd.AddCommand(pairRemoveCmd)
+	pairCmd.AddCommand(pairListCmd)
+}
@@ -4,8 +4,11 @@ Copyright © 2025 NAME HERE dino.danic@gmail.com
 package cmd
 
 import (
+	"fmt"
 	"os"
 
+	"github.com/dinoDanic/diny/config"
+	"github.com/dinoDanic/diny/ui"
 	"github.com/dinoDanic/diny/update"
 	"github.com/spf13/cobra"
 )
@@ -24,6 +27,24 @@ It helps you maintain clean, consistent commit history without
 spending time manually writing messages.
 `,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		noInteractive, _ := cmd.Flags().GetBool("no-interactive")
+		noInput, _ := cmd.Flags().GetBool("no-input")
+		if noInteractive || noInput {
+			ui.SetInteractive(false)
+		}
+
+		if profile, _ := cmd.Flags().GetString("profile"); profile != "" {
+			if err := config.SelectProfile(profile); err != nil {
+				ui.RenderError(fmt.Sprintf("--profile: %v", err))
+				os.Exit(1)
+			}
+		}
+
+		// Keep stdout clean for scripts and hooks
+		if !ui.IsInteractive() {
+			return
+		}
+
 		checker := update.NewUpdateChecker(Version)
 		checker.CheckForUpdate()
 	},
@@ -47,6 +68,11 @@ func init() {
 	// will be global for your application.
 
 	// rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.diny.yaml)")
+	rootCmd.PersistentFlags().String("profile", "", "Use a named profile from the global config (~/.config/diny/config.json)")
+	rootCmd.PersistentFlags().Bool("no-interactive", false, "Never prompt or draw spinners, as when stdin or stdout is not a terminal")
+	// --no-input is the former name, still used by installed git hooks
+	rootCmd.PersistentFlags().Bool("no-input", false, "Same as --no-interactive")
+	rootCmd.PersistentFlags().MarkHidden("no-input")
 
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
@@ -7,10 +7,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 
 	"github.com/charmbracelet/huh"
 	"github.com/dinoDanic/diny/config"
 	"github.com/dinoDanic/diny/git"
+	"github.com/dinoDanic/diny/ui"
 	"github.com/spf13/cobra"
 )
 
@@ -23,12 +25,21 @@ var showConfigCmd = &cobra.Command{
 If no configuration exists, you'll be prompted to create one through the interactive setup.
 
 The configuration includes:
-- Emoji: Whether to use emoji prefixes in commit messages
+- Emoji: Whether to use emoji, the type-to-emoji table (emojiMap), unicode or
+  :shortcode: rendering (emojiStyle) and placement (emojiPlacement)
 - Conventional: Whether to use Conventional Commits format
 - Tone: Professional, casual, or friendly language style
 - Length: Short, normal, or detailed commit message length
-
-Configuration is stored in .git/diny-config.json in your git repository.`,
+- Ticket: Optional branch regex (ticketPattern) and placement (ticketFormat)
+- Trailers: Optional Signed-off-by (signoff) and static trailers (trailers)
+- Formatting: subjectMaxLength, bodyWrap and bulletStyle for generated messages
+- Backend: The diny server (default) or your own Ollama server (backend,
+  ollamaUrl, defaulting to http://127.0.0.1:11434); the diff is sent there
+
+Configuration is stored in .git/diny-config.json in your git repository.
+Without it, a named profile from the global config (~/.config/diny/config.json)
+is used when one of its rules matches or it is the default profile.
+Select a profile explicitly with --profile.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		showUserConfig()
 	},
@@ -46,7 +57,21 @@ func showUserConfig() {
 	configExists := configFileExists(gitRoot)
 
 	if !configExists {
+		// A global profile may apply even without a repository config
+		if profileConfig, err := config.Load(); err == nil && profileConfig != nil {
+			displayConfig(*profileConfig)
+			return
+		}
+
 		fmt.Println("🔧 No configuration found!")
+
+		if !ui.IsInteractive() {
+			fmt.Println("Using the defaults, run 'diny init' to configure.")
+			fmt.Println()
+			displayConfig(config.Default())
+			return
+		}
+
 		fmt.Println("Diny needs to be configured before use.")
 		fmt.Println()
 
@@ -112,12 +137,47 @@ func displayConfig(userConfig config.UserConfig) {
 	fmt.Println()
 	fmt.Println("⚙️  Diny Configuration")
 	fmt.Println("=======================")
-	fmt.Printf("📁 Location: .git/diny-config.json\n")
+	location := config.Source()
+	if location == "" {
+		location = ".git/diny-config.json"
+	}
+	fmt.Printf("📁 Location: %s\n", location)
 	fmt.Println()
 	fmt.Printf("🎨 Use Emoji: %t\n", userConfig.UseEmoji)
+	if userConfig.UseEmoji {
+		fmt.Printf("   Style: %s, placement: %s\n", userConfig.EmojiStyleOrDefault(), userConfig.EmojiPlacementOrDefault())
+		types := make([]string, 0, len(userConfig.EmojiMap))
+		for commitType := range userConfig.EmojiMap {
+			types = append(types, commitType)
+		}
+		sort.Strings(types)
+		for _, commitType := range types {
+			fmt.Printf("   %s → %s\n", commitType, userConfig.EmojiMap[commitType])
+		}
+	}
 	fmt.Printf("📋 Conventional: %t\n", userConfig.UseConventional)
 	fmt.Printf("💬 Tone: %s\n", userConfig.Tone)
 	fmt.Printf("📏 Length: %s\n", userConfig.Length)
+	if userConfig.TicketPattern != "" {
+		fmt.Printf("🎫 Ticket: %s (%s)\n", userConfig.TicketPattern, userConfig.TicketFormatOrDefault())
+	}
+	fmt.Printf("✂️  Subject max length: %d, body wrap: %d, bullet: %s\n",
+		userConfig.SubjectMaxLengthOrDefault(), userConfig.BodyWrapOrDefault(), userConfig.BulletStyleOrDefault())
+	if userConfig.SkipHooks {
+		fmt.Println("⏭️  Skip hooks: true (commits use --no-verify)")
+	}
+	if userConfig.Signoff {
+		fmt.Printf("✍️  Signoff: %t\n", userConfig.Signoff)
+	}
+	for _, trailer := range userConfig.Trailers {
+		fmt.Printf("🏷️  Trailer: %s\n", trailer)
+	}
+	if userConfig.Backend != "" {
+		fmt.Printf("🖥️  Backend: %s\n", userConfig.Backend)
+	}
+	if userConfig.BackendOrDefault() == config.BackendOllama {
+		fmt.Printf("🔗 Ollama URL: %s\n", userConfig.OllamaURLOrDefault())
+	}
 	fmt.Println()
 	fmt.Println("💡 To modify configuration, run: diny init")
 }
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"github.com/dinoDanic/diny/commit"
+	"github.com/spf13/cobra"
+)
+
+var splitCmd = &cobra.Command{
+	Use:   "split",
+	Short: "Split the staged changes into several atomic commits",
+	Long: `Split staged changes that mix unrelated work into several commits.
+
+diny groups the hunks of the staged diff by area (documentation, CI and
+otherwise directory), generates a message for each group and lets you move
+hunks between commits before anything is committed. The commits are then
+created in order by staging each group with git apply --cached.
+
+If a group does not apply or a commit fails, the commits made so far are
+undone and the original staged changes are restored.
+
+Examples:
+  git add -A
+  diny split`,
+	Run: func(cmd *cobra.Command, args []string) {
+		commit.Split(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(splitCmd)
+}
@@ -20,14 +20,25 @@ You can analyze commits from:
 - A date range
 
 This will show you statistics about your commit message style,
-including conventional commit usage, average length, and common patterns.`,
+including conventional commit usage, average length, and common patterns.
+
+--date or --from and --to select the period without the menu. Without a
+terminal, or with --no-interactive, today's commits are analyzed by default.
+
+Examples:
+  diny timeline
+  diny timeline --date "15 01 2025"
+  diny timeline --from "01 01 2025" --to "31 01 2025"`,
 	Run: func(cmd *cobra.Command, args []string) {
-		timeline.Main()
+		timeline.Main(cmd, args)
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(timelineCmd)
+	timelineCmd.Flags().String("date", "", "Analyze the commits of one day, DD MM YYYY")
+	timelineCmd.Flags().String("from", "", "Start of the period to analyze, DD MM YYYY")
+	timelineCmd.Flags().String("to", "", "End of the period to analyze, DD MM YYYY")
 
 	// Here you will define your flags and configuration settings.
 
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"github.com/dinoDanic/diny/commit"
+	"github.com/spf13/cobra"
+)
+
+var undoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Undo the last commit made by diny, keeping its changes staged",
+	Long: `Undo the last commit when it was made by diny and is not pushed yet.
+
+HEAD is soft-reset to the commit it was made on, or to the commit it amended,
+so the index is exactly as it was before committing. The message is kept in
+the history, where diny history reuse 1 lets you edit and commit it again.
+
+diny refuses to undo commits it did not create, commits that are already
+pushed and the first commit of a repository.
+
+Examples:
+  diny undo
+  diny history reuse 1`,
+	Run: func(cmd *cobra.Command, args []string) {
+		commit.Undo(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(undoCmd)
+
+	undoCmd.Flags().BoolP("yes", "y", false, "Do not ask for confirmation")
+}
@@ -0,0 +1,184 @@
+package commit
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/dinoDanic/diny/apidiff"
+	"github.com/dinoDanic/diny/config"
+	"github.com/dinoDanic/diny/conventional"
+	"github.com/dinoDanic/diny/format"
+	"github.com/dinoDanic/diny/git"
+)
+
+// fileReader returns the content of a repository path on one side of a diff,
+// or nil when the path does not exist there.
+type fileReader func(path string) []byte
+
+func revisionReader(rev string) fileReader {
+	return func(path string) []byte {
+		return git.ShowFile(rev, path)
+	}
+}
+
+func worktreeReader() fileReader {
+	root, _ := git.FindGitRoot()
+	return func(path string) []byte {
+		content, err := os.ReadFile(filepath.Join(root, path))
+		if err != nil {
+			return nil
+		}
+		return content
+	}
+}
+
+// DetectBreaking reports incompatible changes to the exported Go and
+// TypeScript API in the changes source selects, whose diff is gitDiff.
+func DetectBreaking(gitDiff string, source DiffSource) []apidiff.Change {
+	before, after, ok := source.sides()
+	if !ok {
+		return nil
+	}
+	return detectBreaking(gitDiff, before, after)
+}
+
+// DetectAmendBreaking is DetectBreaking for the diff of GetAmendDiff.
+func DetectAmendBreaking(gitDiff string, all bool) []apidiff.Change {
+	if !git.HasParent("HEAD") {
+		return nil
+	}
+
+	after := revisionReader("")
+	if all {
+		after = worktreeReader()
+	}
+	return detectBreaking(gitDiff, "HEAD^", after)
+}
+
+// sides returns the revision before the change and where the files after it
+// are read from. It fails when there is no before, e.g. for the first commit.
+func (s DiffSource) sides() (string, fileReader, bool) {
+	orHead := func(rev string) string {
+		if rev == "" {
+			return "HEAD"
+		}
+		return rev
+	}
+
+	switch {
+	case s.Range != "":
+		if from, to, found := strings.Cut(s.Range, "..."); found {
+			base, err := git.MergeBase(orHead(from), orHead(to))
+			if err != nil {
+				return "", nil, false
+			}
+			return base, revisionReader(orHead(to)), true
+		}
+		if from, to, found := strings.Cut(s.Range, ".."); found {
+			return orHead(from), revisionReader(orHead(to)), true
+		}
+		return s.Range, worktreeReader(), true
+	case s.Commit != "":
+		if !git.HasParent(s.Commit) {
+			return "", nil, false
+		}
+		return s.Commit + "^", revisionReader(s.Commit), true
+	}
+
+	if _, err := git.GetHeadHash(); err != nil {
+		return "", nil, false
+	}
+	if s.All {
+		return "HEAD", worktreeReader(), true
+	}
+	return "HEAD", revisionReader(""), true
+}
+
+func detectBreaking(gitDiff, beforeRev string, after fileReader) []apidiff.Change {
+	before := revisionReader(beforeRev)
+
+	var files []apidiff.File
+	for _, path := range withPackageFiles(diffPaths(gitDiff), beforeRev) {
+		if apidiff.Supported(path) {
+			files = append(files, apidiff.File{Path: path, Before: before(path), After: after(path)})
+		}
+	}
+
+	return apidiff.Detect(files)
+}
+
+// withPackageFiles adds the other Go files of every package directory in
+// paths, so a declaration moved into a file the diff does not touch is still
+// found in its package.
+func withPackageFiles(paths []string, rev string) []string {
+	seen := make(map[string]bool, len(paths))
+	var dirs []string
+	for _, path := range paths {
+		seen[path] = true
+		if dir := filepath.Dir(path); strings.HasSuffix(path, ".go") && !slices.Contains(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+
+	for _, dir := range dirs {
+		siblings, err := git.ListTree(rev, dir)
+		if err != nil {
+			continue
+		}
+		for _, path := range siblings {
+			if strings.HasSuffix(path, ".go") && !seen[path] {
+				seen[path] = true
+				paths = append(paths, path)
+			}
+		}
+	}
+
+	return paths
+}
+
+// markBreaking marks commitMessage as a breaking change: "!" after a
+// conventional type, a body paragraph listing changes and a BREAKING CHANGE
+// footer. A message that already has the footer only gets the "!".
+func markBreaking(commitMessage string, changes []apidiff.Change, userConfig *config.UserConfig) string {
+	if len(changes) == 0 {
+		return commitMessage
+	}
+
+	parsed := conventional.Parse(commitMessage)
+
+	_, hasFooter := parsed.Footer("BREAKING CHANGE")
+	if _, found := parsed.Footer("BREAKING-CHANGE"); found {
+		hasFooter = true
+	}
+
+	if hasFooter {
+		// Parse sets Breaking for the footer, so the header gains the "!"
+		if parsed.Conventional && parsed.FormatHeader() != parsed.Header {
+			return format.FitHeader(parsed.String(), userConfig.SubjectMaxLengthOrDefault())
+		}
+		return commitMessage
+	}
+
+	bullet := userConfig.BulletStyleOrDefault()
+	explanation := "Breaking API changes:"
+	for _, change := range changes {
+		explanation += "\n" + bullet + " " + change.String()
+	}
+
+	summary := changes[0].String()
+	if len(changes) > 1 {
+		summary = fmt.Sprintf("%d incompatible changes to the exported API, listed above", len(changes))
+	}
+
+	parsed.Breaking = true
+	if parsed.Body != "" {
+		parsed.Body += "\n\n"
+	}
+	parsed.Body = format.Body(parsed.Body+explanation, userConfig.BodyWrapOrDefault(), bullet)
+	parsed.Footers = append([]conventional.Footer{{Token: "BREAKING CHANGE", Value: summary}}, parsed.Footers...)
+
+	return format.FitHeader(parsed.String(), userConfig.SubjectMaxLengthOrDefault())
+}
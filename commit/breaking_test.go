@@ -0,0 +1,94 @@
+package commit
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/dinoDanic/diny/apidiff"
+	"github.com/dinoDanic/diny/config"
+)
+
+func TestMarkBreaking(t *testing.T) {
+	changes := []apidiff.Change{{Where: "store", Description: "removed func Close"}}
+
+	tests := []struct {
+		name    string
+		message string
+		want    string
+	}{
+		{
+			name:    "conventional",
+			message: "refactor(store): simplify connection handling\n\nRefs: PAY-1",
+			want:    "refactor(store)!: simplify connection handling\n\nBreaking API changes:\n- store: removed func Close\n\nBREAKING CHANGE: store: removed func Close\nRefs: PAY-1",
+		},
+		{
+			name:    "free-form",
+			message: "Simplify connection handling",
+			want:    "Simplify connection handling\n\nBreaking API changes:\n- store: removed func Close\n\nBREAKING CHANGE: store: removed func Close",
+		},
+		{
+			name:    "footer already present",
+			message: "refactor: drop Close\n\nBREAKING CHANGE: Close is gone",
+			want:    "refactor!: drop Close\n\nBREAKING CHANGE: Close is gone",
+		},
+		{
+			name:    "already marked",
+			message: "refactor!: drop Close\n\nBREAKING CHANGE: Close is gone",
+			want:    "refactor!: drop Close\n\nBREAKING CHANGE: Close is gone",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := markBreaking(tt.message, changes, nil); got != tt.want {
+				t.Errorf("markBreaking() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+
+	long := []apidiff.Change{{Where: "github.com/example/project/store", Description: "changed func Open from func(string) (*DB, error) to func(context.Context, string, ...Option) (*DB, error)"}}
+	for _, line := range strings.Split(markBreaking("refactor: pass a context to Open", long, nil), "\n") {
+		if strings.HasPrefix(line, "BREAKING CHANGE:") {
+			continue
+		}
+		if n := utf8.RuneCountInString(line); n > config.DefaultBodyWrap {
+			t.Errorf("line %q has %d characters, want at most %d", line, n, config.DefaultBodyWrap)
+		}
+	}
+
+	if got := markBreaking("fix: typo", nil, nil); got != "fix: typo" {
+		t.Errorf("markBreaking() without changes = %q", got)
+	}
+}
+
+func TestDetectBreakingMovedDeclaration(t *testing.T) {
+	initRepo(t)
+	if err := os.Mkdir("store", 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	writeFile(t, "store/a.go", "package store\n\nfunc Open() {}\n\nfunc Close() {}\n")
+	writeFile(t, "store/b.go", "package store\n\nfunc Reset() {}\n")
+	runGit(t, "add", ".")
+	runGit(t, "commit", "-q", "-m", "init")
+
+	writeFile(t, "store/a.go", "package store\n\nfunc Open() {}\n")
+	writeFile(t, "store/b.go", "package store\n\nfunc Reset() {}\n\nfunc Close() {}\n")
+	runGit(t, "add", ".")
+
+	// Only a.go is in the diff, Close is still declared in the package
+	gitDiff := "diff --git a/store/a.go b/store/a.go\n"
+	if changes := detectBreaking(gitDiff, "HEAD", revisionReader("")); len(changes) != 0 {
+		t.Errorf("detectBreaking() = %v, want no changes", changes)
+	}
+
+	writeFile(t, "store/b.go", "package store\n\nfunc Reset() {}\n")
+	runGit(t, "add", ".")
+
+	changes := detectBreaking(gitDiff, "HEAD", revisionReader(""))
+	if len(changes) != 1 || changes[0].String() != "store: removed func Close" {
+		t.Errorf("detectBreaking() = %v", changes)
+	}
+}
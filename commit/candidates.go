@@ -0,0 +1,129 @@
+package commit
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/huh"
+	"github.com/dinoDanic/diny/config"
+	"github.com/dinoDanic/diny/groq"
+	"github.com/dinoDanic/diny/ui"
+)
+
+// MaxCandidates caps --candidates so one commit does not flood the API.
+const MaxCandidates = 5
+
+// CreateCandidates requests n commit messages concurrently, each asked to
+// take a different angle. Duplicates are dropped; an error is returned only
+// when no candidate could be generated.
+func CreateCandidates(gitDiff string, examples []string, userConfig *config.UserConfig, n int) ([]string, error) {
+	results := make([]Message, n)
+	errs := make([]error, n)
+
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var refinement *groq.Refinement
+			if n > 1 {
+				refinement = &groq.Refinement{Variant: i + 1, Variants: n}
+			}
+			results[i], errs[i] = RefineCommitMessage(gitDiff, examples, refinement, userConfig)
+		}()
+	}
+	wg.Wait()
+
+	var candidates []string
+	seen := make(map[string]bool)
+	for i, result := range results {
+		if errs[i] != nil || seen[result.Text] {
+			continue
+		}
+		seen[result.Text] = true
+		candidates = append(candidates, result.Text)
+	}
+
+	if len(candidates) == 0 {
+		return nil, errs[0]
+	}
+
+	return candidates, nil
+}
+
+// HandleCandidatesFlow lets the user pick one of several candidates, or
+// merge their ideas with feedback, and continues with the regular commit flow.
+func HandleCandidatesFlow(candidates []string, fullPrompt string, userConfig *config.UserConfig, opts Options) {
+	if len(candidates) == 1 {
+		HandleCommitFlow(candidates[0], fullPrompt, userConfig, opts)
+		return
+	}
+
+	for i, candidate := range candidates {
+		ui.RenderBox(fmt.Sprintf("Candidate %d", i+1), candidate)
+	}
+
+	choice := candidatePrompt(candidates)
+
+	if choice == "merge" {
+		customInput := customInputPrompt("What should the combined message take from the candidates?")
+		opts.Session.AddFeedback(customInput)
+
+		refinement := &groq.Refinement{CombineMessages: candidates, Feedback: customInput}
+
+		var newCommitMessage string
+		err := ui.WithSpinner("Combining candidates with your feedback...", func() error {
+			generated, genErr := RefineCommitMessage(fullPrompt, opts.StyleExamples, refinement, userConfig)
+			newCommitMessage = markBreaking(generated.Text, opts.Breaking, userConfig)
+			return genErr
+		})
+		if err != nil {
+			ui.RenderError(fmt.Sprintf("Error: %v", err))
+			os.Exit(1)
+		}
+
+		opts.Session.AddCandidates(newCommitMessage)
+		HandleCommitFlowWithHistory(newCommitMessage, fullPrompt, userConfig, opts, candidates)
+		return
+	}
+
+	index, _ := strconv.Atoi(choice)
+
+	var others []string
+	for i, candidate := range candidates {
+		if i != index {
+			others = append(others, candidate)
+		}
+	}
+
+	HandleCommitFlowWithHistory(candidates[index], fullPrompt, userConfig, opts, others)
+}
+
+func candidatePrompt(candidates []string) string {
+	var choice string
+
+	options := make([]huh.Option[string], 0, len(candidates)+1)
+	for i, candidate := range candidates {
+		subject, _, _ := strings.Cut(candidate, "\n")
+		options = append(options, huh.NewOption(fmt.Sprintf("%d. %s", i+1, subject), fmt.Sprint(i)))
+	}
+	options = append(options, huh.NewOption("Combine ideas with feedback", "merge"))
+
+	err := huh.NewSelect[string]().
+		Title("🦕 Which message do you want to use?").
+		Description("You can still edit, regenerate or refine it afterwards").
+		Options(options...).
+		Value(&choice).
+		Height(len(options) + 2).
+		Run()
+
+	if err != nil {
+		ui.RenderError(fmt.Sprintf("Error running prompt: %v", err))
+		os.Exit(1)
+	}
+
+	return choice
+}
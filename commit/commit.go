@@ -3,22 +3,183 @@ package commit
 import (
 	"fmt"
 	"os"
+	"slices"
+	"strings"
 
+	"github.com/dinoDanic/diny/apidiff"
+	"github.com/dinoDanic/diny/commitlint"
 	"github.com/dinoDanic/diny/config"
+	"github.com/dinoDanic/diny/git"
+	"github.com/dinoDanic/diny/history"
 	"github.com/dinoDanic/diny/ui"
 	"github.com/spf13/cobra"
 )
-//This file should only work in interactive
+
+// Options are the command line options of diny commit and the session
+// recorded for diny history.
+type Options struct {
+	// GitArgs are passed to git commit as is, e.g. -S or --author=...
+	GitArgs []string
+	// Amend regenerates the message for HEAD and amends it.
+	Amend bool
+	// All commits every change to tracked files, like git commit -a.
+	All bool
+	// Session records the messages of this run; nil disables recording.
+	Session *history.Session
+	// Breaking are the incompatible API changes found in the diff. Every
+	// generated message is marked with them.
+	Breaking []apidiff.Change
+	// StyleExamples are the repository's commit messages every message of
+	// this run is generated to match.
+	StyleExamples []string
+}
+
+// reservedGitArgs are set by diny itself and cannot be passed through.
+var reservedGitArgs = []string{"-m", "--message", "-F", "--file", "-C", "--reuse-message", "-c", "--reedit-message", "-e", "--edit"}
+
+// valueShortFlags are the short git commit flags whose value may be attached,
+// so the rest of a cluster after them is not more flags, as in -Skeyid.
+const valueShortFlags = "Stu"
+
+// reservedGitArg returns the reserved flag arg sets. Long options match by
+// prefix like git's own abbreviations (--mess=msg), short ones anywhere in a
+// cluster (-sm) or with an attached value (-mmsg).
+func reservedGitArg(arg string) (string, bool) {
+	if name, _, _ := strings.Cut(arg, "="); strings.HasPrefix(name, "--") {
+		if len(name) == 2 {
+			return "", false
+		}
+		for _, reserved := range reservedGitArgs {
+			if strings.HasPrefix(reserved, "--") && strings.HasPrefix(reserved, name) {
+				return reserved, true
+			}
+		}
+		return "", false
+	}
+
+	if !strings.HasPrefix(arg, "-") {
+		return "", false
+	}
+	for _, flag := range arg[1:] {
+		short := "-" + string(flag)
+		if slices.Contains(reservedGitArgs, short) {
+			return short, true
+		}
+		if strings.ContainsRune(valueShortFlags, flag) {
+			break
+		}
+	}
+	return "", false
+}
+
+// Main runs diny commit. Without a terminal, or with --yes, there is no
+// menu: the message is printed to stdout, or committed with --yes.
 func Main(cmd *cobra.Command, args []string) {
-	fmt.Println()
+	interactive := ui.IsInteractive()
+	if interactive {
+		fmt.Println()
+	}
+
+	var opts Options
+	opts.Amend, _ = cmd.Flags().GetBool("amend")
+	opts.All, _ = cmd.Flags().GetBool("all")
+	allowPushed, _ := cmd.Flags().GetBool("allow-pushed")
+	candidates, _ := cmd.Flags().GetInt("candidates")
+	yes, _ := cmd.Flags().GetBool("yes")
+
+	if candidates < 1 || candidates > MaxCandidates {
+		ui.RenderError(fmt.Sprintf("--candidates must be between 1 and %d", MaxCandidates))
+		os.Exit(1)
+	}
+
+	if candidates > 1 && (yes || !interactive) {
+		ui.RenderError("--candidates needs the menu to pick a message, it cannot be used with --yes or without a terminal")
+		os.Exit(1)
+	}
+
+	if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+		opts.GitArgs = args[dash:]
+	}
+
+	for _, arg := range opts.GitArgs {
+		if name, reserved := reservedGitArg(arg); reserved {
+			ui.RenderError(fmt.Sprintf("%s cannot be passed to git commit, diny sets the message itself", name))
+			os.Exit(1)
+		}
+	}
 
-	gitDiff, err := GetStagedDiff()
+	if selectHunks, _ := cmd.Flags().GetBool("patch"); selectHunks {
+		if opts.All {
+			ui.RenderError("--patch and --all cannot be used together")
+			os.Exit(1)
+		}
+		if !interactive {
+			ui.RenderError("--patch is interactive, run it in a terminal")
+			os.Exit(1)
+		}
+
+		ok, err := SelectHunks()
+		if err != nil {
+			ui.RenderError(fmt.Sprintf("Failed to update the index: %v", err))
+			os.Exit(1)
+		}
+		if !ok {
+			ui.RenderTitle("Bye!")
+			os.Exit(0)
+		}
+	}
+
+	var gitDiff []byte
+	var err error
+
+	if opts.Amend {
+		if !allowPushed {
+			if git.IsPushed("HEAD") {
+				ui.RenderWarning("HEAD has already been pushed to its upstream, amending it would rewrite published history.\nUse --allow-pushed if you really want to.")
+				os.Exit(1)
+			}
+
+			remotes, err := git.RemoteBranchesContaining("HEAD")
+			if err != nil {
+				ui.RenderError(fmt.Sprintf("Failed to check where HEAD is pushed: %v", err))
+				os.Exit(1)
+			}
+			if len(remotes) > 0 {
+				ui.RenderWarning(fmt.Sprintf("HEAD has already been pushed to %s, amending it would rewrite published history.\nUse --allow-pushed if you really want to.", strings.Join(remotes, ", ")))
+				os.Exit(1)
+			}
+		}
+
+		var previousMessage string
+		previousMessage, err = git.GetCommitMessage("HEAD")
+		if err != nil {
+			ui.RenderError(fmt.Sprintf("Failed to read HEAD: %v", err))
+			os.Exit(1)
+		}
+		if interactive {
+			ui.RenderBox("Current commit message", previousMessage)
+		}
+
+		gitDiff, err = GetAmendDiff(opts.All)
+	} else {
+		gitDiff, err = GetDiff(DiffSource{All: opts.All})
+	}
 
 	if err != nil {
 		ui.RenderError(fmt.Sprintf("Failed to get git diff: %v", err))
 		os.Exit(1)
 	}
 
+	if len(gitDiff) == 0 && opts.Amend {
+		ui.RenderWarning("The amended commit would be empty.")
+		os.Exit(0)
+	}
+
+	if len(gitDiff) == 0 && opts.All {
+		ui.RenderWarning("No changes to tracked files found.")
+		os.Exit(0)
+	}
+
 	if len(gitDiff) == 0 {
 		ui.RenderWarning("No staged changes found. Stage files first with `git add`.")
 		os.Exit(0)
@@ -26,12 +187,50 @@ func Main(cmd *cobra.Command, args []string) {
 
 	diff := string(gitDiff)
 
+	if opts.Amend {
+		opts.Breaking = DetectAmendBreaking(diff, opts.All)
+	} else {
+		opts.Breaking = DetectBreaking(diff, DiffSource{All: opts.All})
+	}
+
+	if len(opts.Breaking) > 0 && interactive {
+		var lines []string
+		for _, change := range opts.Breaking {
+			lines = append(lines, "• "+change.String())
+		}
+		ui.RenderWarning("Breaking API changes found, the message will say so:\n\n" + strings.Join(lines, "\n"))
+	}
+
 	userConfig, err := config.Load()
+	opts.Session = history.NewSession("commit", diff, userConfig)
+	opts.StyleExamples = StyleExamples(diff, StyleExampleCount)
+
+	if candidates > 1 {
+		var messages []string
+		err = ui.WithSpinner(fmt.Sprintf("Generating %d commit messages...", candidates), func() error {
+			var genErr error
+			messages, genErr = CreateCandidates(diff, opts.StyleExamples, userConfig, candidates)
+			return genErr
+		})
+
+		if err != nil {
+			ui.RenderError(fmt.Sprintf("%v", err))
+			os.Exit(1)
+		}
+
+		for i := range messages {
+			messages[i] = markBreaking(messages[i], opts.Breaking, userConfig)
+		}
+
+		opts.Session.AddCandidates(messages...)
+		HandleCandidatesFlow(messages, diff, userConfig, opts)
+		return
+	}
 
 	var commitMessage string
 	err = ui.WithSpinner("Generating your commit message...", func() error {
-		var genErr error
-		commitMessage, genErr = CreateCommitMessage(diff, userConfig)
+		generated, genErr := CreateCommitMessage(diff, opts.StyleExamples, userConfig)
+		commitMessage = markBreaking(generated.Text, opts.Breaking, userConfig)
 		return genErr
 	})
 
@@ -40,5 +239,35 @@ func Main(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	HandleCommitFlow(commitMessage, diff, userConfig)
+	opts.Session.AddCandidates(commitMessage)
+
+	if yes || !interactive {
+		finishWithoutMenu(commitMessage, userConfig, opts, yes)
+		return
+	}
+
+	HandleCommitFlow(commitMessage, diff, userConfig, opts)
+}
+
+// finishWithoutMenu commits the message when yes is set and otherwise prints
+// it to stdout, so scripts get the same result as diny message.
+func finishWithoutMenu(commitMessage string, userConfig *config.UserConfig, opts Options, yes bool) {
+	if violations := LintCommitMessage(commitMessage); len(violations) > 0 {
+		fmt.Fprintf(os.Stderr, "commitlint found problems with this message:\n%s\n", commitlint.Format(violations))
+	}
+
+	if !yes {
+		opts.Session.Finish(history.Printed, commitMessage)
+		fmt.Print(commitMessage)
+		fmt.Fprintln(os.Stderr, "\nPrinted the message instead of showing the menu. Pass --yes to commit it.")
+		return
+	}
+
+	if ui.IsInteractive() {
+		ui.RenderBox("Commit message", commitMessage)
+	}
+
+	if err := createCommit(commitMessage, userConfig, opts); err != nil {
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,40 @@
+package commit
+
+import "testing"
+
+func TestReservedGitArg(t *testing.T) {
+	tests := []struct {
+		arg  string
+		want string
+	}{
+		{"-m", "-m"},
+		{"-mmsg", "-m"},
+		{"-Ffile", "-F"},
+		{"-Cabc", "-C"},
+		{"-cHEAD", "-c"},
+		{"--message=fix", "--message"},
+		{"--file", "--file"},
+		{"--edit", "--edit"},
+		{"-sm", "-m"},
+		{"-asF", "-F"},
+		{"--mess=fix", "--message"},
+		{"--fil", "--file"},
+		{"--reu", "--reuse-message"},
+		{"--e", "--edit"},
+		{"-S", ""},
+		{"-SABCDEF", ""},
+		{"-s", ""},
+		{"--", ""},
+		{"--no-edit", ""},
+		{"--author=Dino <dino@example.com>", ""},
+		{"--cleanup=strip", ""},
+		{"--messages", ""},
+	}
+
+	for _, tt := range tests {
+		got, reserved := reservedGitArg(tt.arg)
+		if got != tt.want || reserved != (tt.want != "") {
+			t.Errorf("reservedGitArg(%q) = %q, %t, want %q", tt.arg, got, reserved, tt.want)
+		}
+	}
+}
@@ -1,16 +1,93 @@
 package commit
 
 import (
+	"fmt"
+	"time"
+
+	"github.com/dinoDanic/diny/commitlint"
 	"github.com/dinoDanic/diny/config"
+	"github.com/dinoDanic/diny/git"
 	"github.com/dinoDanic/diny/groq"
+	"github.com/dinoDanic/diny/learn"
+	"github.com/dinoDanic/diny/ollama"
 )
 
-func CreateCommitMessage(gitDiff string, userConfig *config.UserConfig) (string, error) {
-	commitMessage, err := groq.CreateCommitMessageWithGroq(gitDiff, userConfig)
+func CreateCommitMessage(gitDiff string, examples []string, userConfig *config.UserConfig) (Message, error) {
+	return RefineCommitMessage(gitDiff, examples, nil, userConfig)
+}
+
+// RefineCommitMessage generates a message for gitDiff that answers
+// refinement, which may be nil, in the style of examples. A message breaking
+// the repository's commitlint rules is regenerated once with the violations.
+func RefineCommitMessage(gitDiff string, examples []string, refinement *groq.Refinement, userConfig *config.UserConfig) (Message, error) {
+	start := time.Now()
+
+	commitMessage, err := generateCommitMessage(gitDiff, examples, refinement, userConfig)
+	if err != nil {
+		return Message{}, err
+	}
+
+	violations := LintCommitMessage(commitMessage)
+	if commitlint.HasErrors(violations) {
+		var retry groq.Refinement
+		if refinement != nil {
+			retry = *refinement
+		}
+		retry.CurrentMessage = commitMessage
+		retry.Violations = nil
+		for _, violation := range violations {
+			if violation.Level == commitlint.Error {
+				retry.Violations = append(retry.Violations, fmt.Sprintf("%s [%s]", violation.Message, violation.Rule))
+			}
+		}
+
+		if retried, err := generateCommitMessage(gitDiff, examples, &retry, userConfig); err == nil {
+			commitMessage = retried
+		}
+	}
+
+	return newMessage(commitMessage, gitDiff, userConfig, time.Since(start)), nil
+}
+
+func generateCommitMessage(gitDiff string, examples []string, refinement *groq.Refinement, userConfig *config.UserConfig) (string, error) {
+	request := groq.CommitRequest{
+		GitDiff:       gitDiff,
+		StyleExamples: examples,
+		Refinement:    refinement,
+	}
+
+	if corrections, err := learn.Load(); err == nil {
+		request.Guidance = learn.Guidance(corrections, learn.GuidanceCount)
+	}
+
+	var commitMessage string
+	var err error
+
+	if userConfig != nil && userConfig.Backend == config.BackendOllama {
+		commitMessage, err = ollama.Generate(userConfig.OllamaURLOrDefault(), buildPrompt(request, userConfig))
+	} else {
+		commitMessage, err = groq.CreateCommitMessageWithGroq(request, userConfig)
+	}
 
 	if err != nil {
 		return "", err
 	}
 
-	return commitMessage, nil
+	return finalizeMessage(commitMessage, userConfig)
+}
+
+// LintCommitMessage validates commitMessage against the repository's
+// commitlint configuration, if there is one.
+func LintCommitMessage(commitMessage string) []commitlint.Violation {
+	gitRoot, err := git.FindGitRoot()
+	if err != nil {
+		return nil
+	}
+
+	lintConfig, err := commitlint.Load(gitRoot)
+	if err != nil || lintConfig == nil {
+		return nil
+	}
+
+	return lintConfig.Lint(commitMessage)
 }
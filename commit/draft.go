@@ -0,0 +1,26 @@
+package commit
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/dinoDanic/diny/git"
+)
+
+// SaveDraft writes message to .git/COMMIT_EDITMSG, where git and GUI clients
+// such as Fork or Sourcetree pick up the message being prepared. It returns
+// the path written.
+func SaveDraft(message string) (string, error) {
+	gitDir, err := git.GetGitDir()
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(gitDir, "COMMIT_EDITMSG")
+	if err := os.WriteFile(path, []byte(message+"\n"), 0644); err != nil {
+		return "", fmt.Errorf("failed to save draft: %w", err)
+	}
+
+	return path, nil
+}
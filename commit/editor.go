@@ -0,0 +1,66 @@
+package commit
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/dinoDanic/diny/conventional"
+	"github.com/dinoDanic/diny/git"
+)
+
+const editorHelp = `
+# Edit the commit message generated by diny.
+# Lines starting with '#' will be ignored. Save and close the editor to
+# continue; an empty message keeps the current one.`
+
+// EditMessage opens commitMessage in the user's editor and returns the
+// edited text without comment lines.
+func EditMessage(commitMessage string) (string, error) {
+	gitDir, err := git.GetGitDir()
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(gitDir, "DINY_EDITMSG")
+	if err := os.WriteFile(path, []byte(commitMessage+"\n"+editorHelp+"\n"), 0644); err != nil {
+		return "", fmt.Errorf("failed to write message file: %w", err)
+	}
+	defer os.Remove(path)
+
+	if err := runEditor(git.GetEditor(), path); err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read edited message: %w", err)
+	}
+
+	return strings.TrimSpace(conventional.StripComments(string(data))), nil
+}
+
+// runEditor starts editor on path attached to the terminal. Editors are run
+// through the shell like git does, so values such as "code --wait" work.
+func runEditor(editor, path string) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		args := append(strings.Fields(editor), path)
+		cmd = exec.Command(args[0], args[1:]...)
+	} else {
+		cmd = exec.Command("sh", "-c", editor+` "$@"`, editor, path)
+	}
+
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("editor %q failed: %w", editor, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package commit
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dinoDanic/diny/conventional"
+	"github.com/dinoDanic/diny/git"
+)
+
+// StyleExampleCount is how many recent commit messages are sent as style examples.
+const StyleExampleCount = 5
+
+// maxExampleLength keeps long commit bodies from crowding out the diff.
+const maxExampleLength = 600
+
+// maxExamplePaths limits the pathspecs passed to git log for large diffs.
+const maxExamplePaths = 20
+
+// StyleExamples samples up to n recent commit messages of the repository so
+// the generated message can mirror its conventions. Commits by the current
+// author that touch the changed paths come first, then other commits touching
+// those paths, then the author's commits and finally any recent commit.
+// Merge, revert and fixup messages are skipped.
+//
+// It runs several git log queries, so callers collect the examples once and
+// reuse them for every message of a session.
+func StyleExamples(gitDiff string, n int) []string {
+	paths := diffPaths(gitDiff)
+	author, _ := git.GetUserEmail()
+
+	type query struct {
+		author string
+		paths  []string
+	}
+
+	var queries []query
+	if author != "" && len(paths) > 0 {
+		queries = append(queries, query{author, paths})
+	}
+	if len(paths) > 0 {
+		queries = append(queries, query{"", paths})
+	}
+	if author != "" {
+		queries = append(queries, query{author, nil})
+	}
+	queries = append(queries, query{"", nil})
+
+	var examples []string
+	for _, q := range queries {
+		messages, err := git.GetRecentCommitMessagesMatching(n*2, q.author, q.paths)
+		if err != nil {
+			return examples
+		}
+
+		for _, message := range messages {
+			header, _, _ := strings.Cut(message, "\n")
+			if conventional.IsAutomatic(header) {
+				continue
+			}
+
+			message = truncateExample(message)
+			if !slices.Contains(examples, message) {
+				examples = append(examples, message)
+			}
+			if len(examples) == n {
+				return examples
+			}
+		}
+	}
+
+	return examples
+}
+
+// truncateExample cuts message to maxExampleLength bytes without splitting
+// a multi-byte character.
+func truncateExample(message string) string {
+	if len(message) <= maxExampleLength {
+		return message
+	}
+
+	cut := maxExampleLength
+	for cut > 0 && !utf8.RuneStart(message[cut]) {
+		cut--
+	}
+	return strings.TrimSpace(message[:cut]) + "\n..."
+}
+
+// diffPaths returns the files a diff touches, from its "diff --git" lines.
+func diffPaths(gitDiff string) []string {
+	var paths []string
+	for _, line := range strings.Split(gitDiff, "\n") {
+		rest, ok := strings.CutPrefix(line, "diff --git ")
+		if !ok {
+			continue
+		}
+
+		_, path, found := strings.Cut(rest, " b/")
+		if !found || slices.Contains(paths, path) {
+			continue
+		}
+
+		paths = append(paths, path)
+		if len(paths) == maxExamplePaths {
+			break
+		}
+	}
+	return paths
+}
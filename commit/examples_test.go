@@ -0,0 +1,43 @@
+package commit
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestDiffPaths(t *testing.T) {
+	diff := `diff --git a/cmd/root.go b/cmd/root.go
+index 1111111..2222222 100644
+--- a/cmd/root.go
++++ b/cmd/root.go
+@@ -1 +1 @@
+diff --git a/old.go b/new.go
+similarity index 90%
+diff --git a/cmd/root.go b/cmd/root.go
+`
+
+	got := diffPaths(diff)
+	want := []string{"cmd/root.go", "new.go"}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("diffPaths() = %q, want %q", got, want)
+	}
+}
+
+func TestTruncateExample(t *testing.T) {
+	message := strings.Repeat("a", maxExampleLength-1) + "é and more"
+
+	got := truncateExample(message)
+	if !utf8.ValidString(got) {
+		t.Errorf("truncateExample() returned invalid UTF-8: %q", got[len(got)-8:])
+	}
+	if want := strings.Repeat("a", maxExampleLength-1) + "\n..."; got != want {
+		t.Errorf("truncateExample() = %q, want %q", got[len(got)-8:], want[len(want)-8:])
+	}
+
+	if short := "fix: keep me"; truncateExample(short) != short {
+		t.Errorf("truncateExample() changed a short message")
+	}
+}
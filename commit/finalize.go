@@ -0,0 +1,32 @@
+package commit
+
+import (
+	"github.com/dinoDanic/diny/config"
+	"github.com/dinoDanic/diny/format"
+)
+
+// finalizeMessage runs the deterministic post-processing pipeline on model
+// output: formatting, emoji, ticket reference and trailers, in that order.
+// The header is fitted to the length limit once more after the emoji and
+// ticket are in place.
+func finalizeMessage(commitMessage string, userConfig *config.UserConfig) (string, error) {
+	commitMessage = format.Message(commitMessage, format.Options{
+		SubjectMaxLength: userConfig.SubjectMaxLengthOrDefault(),
+		BodyWidth:        userConfig.BodyWrapOrDefault(),
+		Bullet:           userConfig.BulletStyleOrDefault(),
+	})
+
+	if userConfig != nil {
+		commitMessage = format.ApplyEmoji(commitMessage, format.EmojiOptions{
+			Enabled:   userConfig.UseEmoji,
+			Map:       userConfig.EmojiMap,
+			Style:     string(userConfig.EmojiStyleOrDefault()),
+			Placement: string(userConfig.EmojiPlacementOrDefault()),
+		})
+	}
+
+	commitMessage = applyTicket(commitMessage, userConfig)
+	commitMessage = format.FitHeader(commitMessage, userConfig.SubjectMaxLengthOrDefault())
+
+	return applyTrailers(commitMessage, userConfig)
+}
@@ -0,0 +1,34 @@
+package commit
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/dinoDanic/diny/apidiff"
+	"github.com/dinoDanic/diny/config"
+)
+
+func TestFinalizeMessageHeaderLength(t *testing.T) {
+	userConfig := &config.UserConfig{UseEmoji: true, UseConventional: true}
+	changes := []apidiff.Change{{Where: "store", Description: "removed func Close"}}
+
+	header := "refactor(store): move connection pooling into the shared store packages"
+	if n := utf8.RuneCountInString(header); n != 71 {
+		t.Fatalf("test header has %d characters, want 71", n)
+	}
+
+	message, err := finalizeMessage(header, userConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	message = markBreaking(message, changes, userConfig)
+
+	got, _, _ := strings.Cut(message, "\n")
+	if n := utf8.RuneCountInString(got); n > config.DefaultSubjectMaxLength {
+		t.Errorf("header %q has %d characters, want at most %d", got, n, config.DefaultSubjectMaxLength)
+	}
+	if !strings.HasPrefix(got, "♻️ refactor(store)!: move connection pooling") {
+		t.Errorf("header = %q", got)
+	}
+}
@@ -1,14 +1,107 @@
 package commit
 
 import (
+	"fmt"
 	"os/exec"
+
+	"github.com/dinoDanic/diny/git"
 )
 
+// diffOptions keep the a/ and b/ prefixes the diff parsers expect whatever
+// diff.noprefix or diff.mnemonicPrefix say.
+var diffOptions = []string{"-U0", "--no-color", "--no-ext-diff", "--src-prefix=a/", "--dst-prefix=b/", "--ignore-all-space", "--ignore-blank-lines"}
+
+var noisePathspecs = []string{":(exclude)*.lock", ":(exclude)*package-lock.json", ":(exclude)*yarn.lock",
+	":(exclude)node_modules/", ":(exclude)dist/", ":(exclude)build/"}
+
+// DiffSource selects the changes to describe. The zero value is the staged
+// changes.
+type DiffSource struct {
+	// All describes the working tree against HEAD, like git commit -a.
+	All bool
+	// Range describes the changes between two revisions, e.g. main..feature.
+	Range string
+	// Commit describes the changes made by a single commit.
+	Commit string
+	// Paths limits the diff to these pathspecs.
+	Paths []string
+}
+
+// Validate reports conflicting sources.
+func (s DiffSource) Validate() error {
+	set := 0
+	for _, ok := range []bool{s.All, s.Range != "", s.Commit != ""} {
+		if ok {
+			set++
+		}
+	}
+
+	if set > 1 {
+		return fmt.Errorf("use only one of --all, --range and --commit")
+	}
+	return nil
+}
+
+// IsStaged reports whether s describes the staged changes.
+func (s DiffSource) IsStaged() bool {
+	return !s.All && s.Range == "" && s.Commit == ""
+}
+
+// GetDiff returns the diff of the changes selected by source.
+func GetDiff(source DiffSource) ([]byte, error) {
+	if err := source.Validate(); err != nil {
+		return nil, err
+	}
+
+	var args []string
+	switch {
+	case source.All:
+		base := "HEAD"
+		if _, err := git.GetHeadHash(); err != nil {
+			base = git.EmptyTree
+		}
+		args = []string{"diff", base}
+	case source.Range != "":
+		args = []string{"diff", source.Range}
+	case source.Commit != "":
+		base := source.Commit + "^"
+		if !git.HasParent(source.Commit) {
+			base = git.EmptyTree
+		}
+		args = []string{"diff", base, source.Commit}
+	default:
+		args = []string{"diff", "--cached"}
+	}
+
+	args = append(args, diffOptions...)
+	args = append(args, "--")
+	args = append(args, source.Paths...)
+	args = append(args, noisePathspecs...)
+
+	return exec.Command("git", args...).Output()
+}
+
 func GetStagedDiff() ([]byte, error) {
-	gitDiffCmd := exec.Command("git", "diff", "--cached",
-		"-U0", "--no-color", "--ignore-all-space", "--ignore-blank-lines",
-		":(exclude)*.lock", ":(exclude)*package-lock.json", ":(exclude)*yarn.lock",
-		":(exclude)node_modules/", ":(exclude)dist/", ":(exclude)build/")
+	return GetDiff(DiffSource{})
+}
+
+// GetAmendDiff returns the combined diff of HEAD and the index against
+// HEAD's parent, which is what an amended commit will contain. With all, the
+// working tree is used instead of the index, as git commit --amend -a does.
+func GetAmendDiff(all bool) ([]byte, error) {
+	base := "HEAD^"
+	if !git.HasParent("HEAD") {
+		base = git.EmptyTree
+	}
+
+	args := []string{"diff", "--cached", base}
+	if all {
+		args = []string{"diff", base}
+	}
+
+	args = append(args, diffOptions...)
+	args = append(args, "--")
+	args = append(args, noisePathspecs...)
 
-	return gitDiffCmd.Output()
-}
\ No newline at end of file
+	return exec.Command("git", args...).Output()
+}
@@ -4,48 +4,68 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"slices"
+	"strings"
 
 	"github.com/charmbracelet/huh"
+	"github.com/dinoDanic/diny/clipboard"
+	"github.com/dinoDanic/diny/commitlint"
 	"github.com/dinoDanic/diny/config"
+	"github.com/dinoDanic/diny/git"
+	"github.com/dinoDanic/diny/groq"
+	"github.com/dinoDanic/diny/history"
+	"github.com/dinoDanic/diny/learn"
 	"github.com/dinoDanic/diny/ui"
 )
 
-func HandleCommitFlow(commitMessage, fullPrompt string, userConfig *config.UserConfig) {
-	HandleCommitFlowWithHistory(commitMessage, fullPrompt, userConfig, []string{})
+func HandleCommitFlow(commitMessage, fullPrompt string, userConfig *config.UserConfig, opts Options) {
+	HandleCommitFlowWithHistory(commitMessage, fullPrompt, userConfig, opts, []string{})
 }
 
-func HandleCommitFlowWithHistory(commitMessage, fullPrompt string, userConfig *config.UserConfig, previousMessages []string) {
+func HandleCommitFlowWithHistory(commitMessage, fullPrompt string, userConfig *config.UserConfig, opts Options, previousMessages []string) {
 
 	ui.RenderBox("Commit message", commitMessage)
 
+	if violations := LintCommitMessage(commitMessage); len(violations) > 0 {
+		ui.RenderWarning("commitlint found problems with this message:\n\n" + commitlint.Format(violations))
+	}
+
 	choice := choicePrompt("What would you like to do next?")
 
 	switch choice {
 	case "commit":
-		// ui.RenderTitle("Creating commit...")
-		commitCmd := exec.Command("git", "commit", "--no-verify", "-m", commitMessage)
-		err := commitCmd.Run()
+		if err := createCommit(commitMessage, userConfig, opts); err != nil {
+			HandleCommitFlowWithHistory(commitMessage, fullPrompt, userConfig, opts, previousMessages)
+		}
+	case "edit":
+		editedMessage, err := EditMessage(commitMessage)
 		if err != nil {
-			ui.RenderError(fmt.Sprintf("Commit failed: %v", err))
-			os.Exit(1)
+			ui.RenderError(fmt.Sprintf("Error: %v", err))
+			HandleCommitFlowWithHistory(commitMessage, fullPrompt, userConfig, opts, previousMessages)
+			return
 		}
-		ui.RenderTitle("Commited!")
-	case "regenerate":
-		modifiedPrompt := fullPrompt
-		if len(previousMessages) > 0 {
-			modifiedPrompt += "\n\nPrevious commit messages that were not satisfactory:\n"
-			for i, msg := range previousMessages {
-				modifiedPrompt += fmt.Sprintf("%d. %s\n", i+1, msg)
+
+		if editedMessage == "" {
+			ui.RenderWarning("Empty message, keeping the previous one.")
+			HandleCommitFlowWithHistory(commitMessage, fullPrompt, userConfig, opts, previousMessages)
+			return
+		}
+
+		ui.RenderBox("Edited commit message", editedMessage)
+		if editedChoicePrompt() == "commit" {
+			if err := createCommit(editedMessage, userConfig, opts); err == nil {
+				return
 			}
-			modifiedPrompt += "\nPlease generate a different commit message that avoids the style and approach of the previous ones."
-		} else {
-			modifiedPrompt += "\n\nPlease provide an alternative commit message with a different approach or focus."
 		}
 
+		HandleCommitFlowWithHistory(editedMessage, fullPrompt, userConfig, opts, previousMessages)
+	case "regenerate":
+		refinement := &groq.Refinement{RejectedMessages: append(slices.Clone(previousMessages), commitMessage)}
+
 		var newCommitMessage string
 		err := ui.WithSpinner("Generating alternative commit message...", func() error {
-			var genErr error
-			newCommitMessage, genErr = CreateCommitMessage(modifiedPrompt, userConfig)
+			generated, genErr := RefineCommitMessage(fullPrompt, opts.StyleExamples, refinement, userConfig)
+			newCommitMessage = markBreaking(generated.Text, opts.Breaking, userConfig)
 			return genErr
 		})
 		if err != nil {
@@ -53,17 +73,25 @@ func HandleCommitFlowWithHistory(commitMessage, fullPrompt string, userConfig *c
 			os.Exit(1)
 		}
 
+		opts.Session.AddRegeneration()
+		opts.Session.AddCandidates(newCommitMessage)
+
 		updatedHistory := append(previousMessages, commitMessage)
-		HandleCommitFlowWithHistory(newCommitMessage, fullPrompt, userConfig, updatedHistory)
+		HandleCommitFlowWithHistory(newCommitMessage, fullPrompt, userConfig, opts, updatedHistory)
 	case "custom":
 		customInput := customInputPrompt("What changes would you like to see in the commit message?")
+		opts.Session.AddFeedback(customInput)
 
-		modifiedPrompt := fullPrompt + fmt.Sprintf("\n\nCurrent commit message:\n%s\n\nUser feedback: %s\n\nPlease generate a new commit message that addresses the user's feedback.", commitMessage, customInput)
+		refinement := &groq.Refinement{
+			RejectedMessages: previousMessages,
+			CurrentMessage:   commitMessage,
+			Feedback:         customInput,
+		}
 
 		var newCommitMessage string
 		err := ui.WithSpinner("Refining commit message with your feedback...", func() error {
-			var genErr error
-			newCommitMessage, genErr = CreateCommitMessage(modifiedPrompt, userConfig)
+			generated, genErr := RefineCommitMessage(fullPrompt, opts.StyleExamples, refinement, userConfig)
+			newCommitMessage = markBreaking(generated.Text, opts.Breaking, userConfig)
 			return genErr
 		})
 		if err != nil {
@@ -71,14 +99,104 @@ func HandleCommitFlowWithHistory(commitMessage, fullPrompt string, userConfig *c
 			os.Exit(1)
 		}
 
+		opts.Session.AddCandidates(newCommitMessage)
+
 		updatedHistory := append(previousMessages, commitMessage)
-		HandleCommitFlowWithHistory(newCommitMessage, fullPrompt, userConfig, updatedHistory)
+		HandleCommitFlowWithHistory(newCommitMessage, fullPrompt, userConfig, opts, updatedHistory)
+	case "copy":
+		method, err := clipboard.Copy(commitMessage)
+		if err != nil {
+			ui.RenderError(err.Error())
+			HandleCommitFlowWithHistory(commitMessage, fullPrompt, userConfig, opts, previousMessages)
+			return
+		}
+
+		opts.Session.Finish(history.Printed, commitMessage)
+		ui.RenderSuccess(fmt.Sprintf("Copied to the %s", method))
+	case "draft":
+		path, err := SaveDraft(commitMessage)
+		if err != nil {
+			ui.RenderError(err.Error())
+			HandleCommitFlowWithHistory(commitMessage, fullPrompt, userConfig, opts, previousMessages)
+			return
+		}
+
+		opts.Session.Finish(history.Printed, commitMessage)
+		ui.RenderSuccess(fmt.Sprintf("Saved the draft to %s", path))
 	case "exit":
+		opts.Session.Finish(history.Abandoned, "")
 		ui.RenderTitle("Bye!")
 		os.Exit(0)
 	}
 }
 
+// createCommit runs git commit with the message and the pass-through
+// arguments. Hooks run unless skipHooks is configured; their output is shown
+// in a box, or directly on the terminal when there are pass-through
+// arguments, and a failed commit is reported so the caller can return to the
+// menu.
+func createCommit(commitMessage string, userConfig *config.UserConfig, opts Options) error {
+	args := []string{"commit"}
+	if opts.Amend {
+		args = append(args, "--amend")
+	}
+	if opts.All {
+		args = append(args, "--all")
+	}
+	if userConfig != nil && userConfig.SkipHooks {
+		args = append(args, "--no-verify")
+	}
+	args = append(args, opts.GitArgs...)
+	args = append(args, "-m", commitMessage)
+
+	// Where diny undo returns to: the parent, or the commit being amended
+	base, _ := git.GetHeadHash()
+
+	gitCmd := exec.Command("git", args...)
+	// Lets the diny git hooks know the message comes from diny commit
+	gitCmd.Env = append(os.Environ(), "DINY_COMMIT=1")
+
+	var output []byte
+	var err error
+	if len(opts.GitArgs) > 0 {
+		// Pass-through arguments such as -S may prompt for a passphrase or
+		// open an editor, so git gets the terminal instead of a spinner
+		gitCmd.Stdin, gitCmd.Stdout, gitCmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+		err = gitCmd.Run()
+	} else {
+		err = ui.WithSpinner("Committing...", func() error {
+			var commitErr error
+			output, commitErr = gitCmd.CombinedOutput()
+			return commitErr
+		})
+	}
+
+	if err != nil {
+		content := strings.TrimSpace(string(output))
+		if content == "" {
+			content = err.Error()
+		}
+		ui.RenderError(fmt.Sprintf("Commit failed: %v\n\n%s", err, content))
+		return err
+	}
+
+	if content := strings.TrimSpace(string(output)); content != "" {
+		ui.RenderBox("git commit", content)
+	}
+
+	// History is best effort and must never fail a commit
+	opts.Session.SetBase(base)
+	opts.Session.Finish(history.Committed, commitMessage)
+	learn.RecordSession(opts.Session)
+
+	if opts.Amend {
+		ui.RenderTitle("Amended!")
+	} else {
+		ui.RenderTitle("Commited!")
+	}
+	return nil
+}
+
 func choicePrompt(message string) string {
 	var choice string
 
@@ -87,12 +205,36 @@ func choicePrompt(message string) string {
 		Description("Select an option using arrow keys or j,k and press Enter").
 		Options(
 			huh.NewOption("Commit this message", "commit"),
+			huh.NewOption("Edit in $EDITOR", "edit"),
 			huh.NewOption("Generate different message", "regenerate"),
 			huh.NewOption("Refine with feedback", "custom"),
+			huh.NewOption("Copy to clipboard", "copy"),
+			huh.NewOption("Save as draft to .git/COMMIT_EDITMSG", "draft"),
 			huh.NewOption("Exit", "exit"),
 		).
 		Value(&choice).
-		Height(6).
+		Height(9).
+		Run()
+
+	if err != nil {
+		ui.RenderError(fmt.Sprintf("Error running prompt: %v", err))
+		os.Exit(1)
+	}
+
+	return choice
+}
+
+func editedChoicePrompt() string {
+	var choice string
+
+	err := huh.NewSelect[string]().
+		Title("🦕 Commit the edited message?").
+		Options(
+			huh.NewOption("Commit now", "commit"),
+			huh.NewOption("Back to menu", "menu"),
+		).
+		Value(&choice).
+		Height(4).
 		Run()
 
 	if err != nil {
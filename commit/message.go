@@ -0,0 +1,87 @@
+package commit
+
+import (
+	"time"
+
+	"github.com/dinoDanic/diny/apidiff"
+	"github.com/dinoDanic/diny/config"
+	"github.com/dinoDanic/diny/conventional"
+)
+
+// Message is a generated commit message with its parsed parts and how it was
+// generated. It is what diny message --format json prints.
+type Message struct {
+	// Text is the complete message, ready for git commit.
+	Text string `json:"message"`
+	// Header is the first line; Subject is its description without the
+	// type, scope and emoji.
+	Header   string                `json:"header"`
+	Subject  string                `json:"subject"`
+	Body     string                `json:"body"`
+	Type     string                `json:"type,omitempty"`
+	Scope    string                `json:"scope,omitempty"`
+	Emoji    string                `json:"emoji,omitempty"`
+	Breaking bool                  `json:"breaking"`
+	Footers  []conventional.Footer `json:"footers"`
+
+	Backend config.Backend `json:"backend"`
+	// Duration is how long generating took, including the commitlint retry.
+	Duration time.Duration `json:"-"`
+	// DurationMs is Duration in milliseconds for JSON consumers.
+	DurationMs int64 `json:"durationMs"`
+	// Files are the paths of the diff the message describes.
+	Files []string `json:"files"`
+	// BreakingChanges are the incompatible API changes found in the diff.
+	BreakingChanges []string `json:"breakingChanges"`
+}
+
+// newMessage parses text and records how it was generated from gitDiff.
+func newMessage(text, gitDiff string, userConfig *config.UserConfig, duration time.Duration) Message {
+	parsed := conventional.Parse(text)
+
+	message := Message{
+		Text:       text,
+		Header:     parsed.Header,
+		Subject:    parsed.Subject,
+		Body:       parsed.Body,
+		Type:       parsed.Type,
+		Scope:      parsed.Scope,
+		Emoji:      parsed.Emoji,
+		Breaking:   parsed.Breaking,
+		Footers:    parsed.Footers,
+		Backend:    userConfig.BackendOrDefault(),
+		Duration:   duration,
+		DurationMs: duration.Milliseconds(),
+		Files:      diffPaths(gitDiff),
+	}
+
+	// Keep JSON arrays as [] rather than null
+	if message.Footers == nil {
+		message.Footers = []conventional.Footer{}
+	}
+	if message.Files == nil {
+		message.Files = []string{}
+	}
+	message.BreakingChanges = []string{}
+
+	return message
+}
+
+// WithBreaking returns m marked as a breaking change when changes are found,
+// see DetectBreaking.
+func (m Message) WithBreaking(changes []apidiff.Change, userConfig *config.UserConfig) Message {
+	if len(changes) == 0 {
+		return m
+	}
+
+	marked := newMessage(markBreaking(m.Text, changes, userConfig), "", userConfig, m.Duration)
+	marked.Files = m.Files
+	for _, change := range changes {
+		marked.BreakingChanges = append(marked.BreakingChanges, change.String())
+	}
+	return marked
+}
+
+func (m Message) String() string {
+	return m.Text
+}
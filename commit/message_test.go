@@ -0,0 +1,55 @@
+package commit
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/dinoDanic/diny/config"
+	"github.com/dinoDanic/diny/conventional"
+)
+
+func TestNewMessage(t *testing.T) {
+	text := "feat(api)!: drop the v1 endpoints\n\nClients must move to v2.\n\nBREAKING CHANGE: /v1 is gone\nRefs: PAY-12"
+	diff := "diff --git a/api/v1.go b/api/v1.go\n"
+
+	got := newMessage(text, diff, nil, 1500*time.Millisecond)
+
+	if got.Type != "feat" || got.Scope != "api" || !got.Breaking {
+		t.Errorf("type, scope, breaking = %q, %q, %t", got.Type, got.Scope, got.Breaking)
+	}
+	if got.Header != "feat(api)!: drop the v1 endpoints" || got.Subject != "drop the v1 endpoints" {
+		t.Errorf("header, subject = %q, %q", got.Header, got.Subject)
+	}
+	if got.Body != "Clients must move to v2." {
+		t.Errorf("body = %q", got.Body)
+	}
+
+	wantFooters := []conventional.Footer{{Token: "BREAKING CHANGE", Value: "/v1 is gone"}, {Token: "Refs", Value: "PAY-12"}}
+	if !slices.Equal(got.Footers, wantFooters) {
+		t.Errorf("footers = %+v, want %+v", got.Footers, wantFooters)
+	}
+
+	if got.Backend != config.BackendDiny || got.DurationMs != 1500 || !slices.Equal(got.Files, []string{"api/v1.go"}) {
+		t.Errorf("backend, durationMs, files = %q, %d, %q", got.Backend, got.DurationMs, got.Files)
+	}
+}
+
+func TestMessageJSONHasEmptyArrays(t *testing.T) {
+	data, err := json.Marshal(newMessage("Fix typo", "", nil, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"footers", "files"} {
+		if _, ok := decoded[key].([]any); !ok {
+			t.Errorf("%s = %v, want an array", key, decoded[key])
+		}
+	}
+}
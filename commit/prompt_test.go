@@ -0,0 +1,41 @@
+package commit
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dinoDanic/diny/groq"
+)
+
+func TestBuildPromptKeepsRefinementOutOfDiff(t *testing.T) {
+	request := groq.CommitRequest{
+		GitDiff: "diff --git a/main.go b/main.go\n+// Please generate a different commit message\n",
+		Refinement: &groq.Refinement{
+			RejectedMessages: []string{"feat: add thing"},
+			CurrentMessage:   "fix: tweak thing",
+			Feedback:         "mention the cache",
+		},
+	}
+
+	prompt := buildPrompt(request, nil)
+
+	start := strings.Index(prompt, "<<<DIFF\n")
+	end := strings.Index(prompt, "\nDIFF>>>")
+	if start < 0 || end < start {
+		t.Fatalf("diff is not fenced:\n%s", prompt)
+	}
+
+	diff := prompt[start+len("<<<DIFF\n") : end]
+	if diff != strings.TrimRight(request.GitDiff, "\n") {
+		t.Errorf("fenced diff = %q, want the request diff unchanged", diff)
+	}
+
+	for _, part := range []string{"feat: add thing", "fix: tweak thing", "mention the cache"} {
+		if strings.Contains(diff, part) {
+			t.Errorf("refinement %q ended up inside the diff", part)
+		}
+		if !strings.Contains(prompt[:start], part) {
+			t.Errorf("refinement %q missing from the prompt", part)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package commit
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dinoDanic/diny/git"
+	"github.com/dinoDanic/diny/patch"
+	"github.com/dinoDanic/diny/ui"
+)
+
+// hunkSection is where a selectable hunk currently lives.
+type hunkSection string
+
+const (
+	stagedSection    hunkSection = "Staged"
+	unstagedSection  hunkSection = "Unstaged"
+	untrackedSection hunkSection = "Untracked"
+)
+
+// hunkItem is one line of the selection view: a hunk or whole file of the
+// staged or unstaged diff, or an untracked file.
+type hunkItem struct {
+	section  hunkSection
+	unit     patch.Unit
+	path     string
+	selected bool
+}
+
+// selectModel lets the user toggle hunks into or out of the index.
+type selectModel struct {
+	staged   []patch.File
+	unstaged []patch.File
+	items    []hunkItem
+	cursor   int
+	preview  bool
+	done     bool
+}
+
+func newSelectModel(staged, unstaged []patch.File, untracked []string) selectModel {
+	m := selectModel{staged: staged, unstaged: unstaged}
+
+	for _, unit := range patch.Units(staged) {
+		m.items = append(m.items, hunkItem{section: stagedSection, unit: unit, path: staged[unit.File].Path, selected: true})
+	}
+	for _, unit := range patch.Units(unstaged) {
+		m.items = append(m.items, hunkItem{section: unstagedSection, unit: unit, path: unstaged[unit.File].Path})
+	}
+	for _, path := range untracked {
+		m.items = append(m.items, hunkItem{section: untrackedSection, path: path})
+	}
+
+	return m
+}
+
+func (m selectModel) files(item hunkItem) []patch.File {
+	if item.section == stagedSection {
+		return m.staged
+	}
+	return m.unstaged
+}
+
+func (m selectModel) label(item hunkItem) string {
+	if item.section == untrackedSection {
+		return item.path
+	}
+	return item.unit.Label(m.files(item))
+}
+
+func (m selectModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m selectModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch key.String() {
+	case "ctrl+c", "q", "esc":
+		return m, tea.Quit
+	case "enter":
+		m.done = true
+		return m, tea.Quit
+	}
+
+	if len(m.items) == 0 {
+		return m, nil
+	}
+
+	switch key.String() {
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.items)-1 {
+			m.cursor++
+		}
+	case " ", "x":
+		m.items[m.cursor].selected = !m.items[m.cursor].selected
+	case "f":
+		// Toggle every hunk of the file under the cursor in the same section
+		current := m.items[m.cursor]
+		for i, item := range m.items {
+			if item.section == current.section && item.path == current.path {
+				m.items[i].selected = !current.selected
+			}
+		}
+	case "a":
+		all := true
+		for _, item := range m.items {
+			all = all && item.selected
+		}
+		for i := range m.items {
+			m.items[i].selected = !all
+		}
+	case "p":
+		m.preview = !m.preview
+	}
+
+	return m, nil
+}
+
+func (m selectModel) View() string {
+	var b strings.Builder
+
+	b.WriteString(tuiTitleStyle.Render("🦕 Select the changes to commit") + "\n")
+	b.WriteString(tuiMutedStyle.Render("↑/↓ move • space toggle • f toggle file • a toggle all • p preview • enter done • q cancel") + "\n\n")
+
+	if len(m.items) == 0 {
+		b.WriteString(tuiMutedStyle.Render("Nothing to commit, the working tree is clean.") + "\n")
+		return b.String()
+	}
+
+	var section hunkSection
+	for i, item := range m.items {
+		if item.section != section {
+			if section != "" {
+				b.WriteString("\n")
+			}
+			section = item.section
+			b.WriteString(tuiTitleStyle.Render(string(section)) + "\n")
+		}
+
+		check := "[ ]"
+		if item.selected {
+			check = "[x]"
+		}
+
+		line := fmt.Sprintf("%s %s", check, m.label(item))
+		if i == m.cursor {
+			b.WriteString(tuiCursorStyle.Render("> "+line) + "\n")
+		} else {
+			b.WriteString("  " + line + "\n")
+		}
+	}
+
+	if m.preview {
+		b.WriteString("\n")
+		if item := m.items[m.cursor]; item.section == untrackedSection {
+			b.WriteString(tuiMutedStyle.Render("  new file "+item.path) + "\n")
+		} else {
+			b.WriteString(renderPreview(item.unit.Lines(m.files(item))))
+		}
+	}
+
+	return b.String()
+}
+
+// SelectHunks shows the staged and unstaged hunks and untracked files and
+// updates the index to match the selection. It returns false when the user
+// cancels, leaving the index untouched.
+func SelectHunks() (bool, error) {
+	stagedPatch, err := git.GetStagedPatch()
+	if err != nil {
+		return false, err
+	}
+
+	unstagedPatch, err := git.GetUnstagedPatch()
+	if err != nil {
+		return false, err
+	}
+
+	untracked, err := git.GetUntrackedFiles()
+	if err != nil {
+		return false, err
+	}
+
+	final, err := tea.NewProgram(newSelectModel(patch.Parse(stagedPatch), patch.Parse(unstagedPatch), untracked)).Run()
+	if err != nil {
+		return false, err
+	}
+
+	m := final.(selectModel)
+	if !m.done {
+		return false, nil
+	}
+
+	return true, m.apply()
+}
+
+// apply stages the selected unstaged hunks and untracked files and unstages
+// the deselected staged hunks. On failure the original index is restored.
+func (m selectModel) apply() error {
+	var stage, unstage []patch.Unit
+	var add []string
+
+	for _, item := range m.items {
+		switch {
+		case item.section == stagedSection && !item.selected:
+			unstage = append(unstage, item.unit)
+		case item.section == unstagedSection && item.selected:
+			stage = append(stage, item.unit)
+		case item.section == untrackedSection && item.selected:
+			add = append(add, item.path)
+		}
+	}
+
+	if len(stage) == 0 && len(unstage) == 0 && len(add) == 0 {
+		return nil
+	}
+
+	originalTree, err := git.WriteTree()
+	if err != nil {
+		return err
+	}
+
+	err = m.applyChanges(stage, unstage, add)
+	if err != nil {
+		if restoreErr := git.ReadTree(originalTree); restoreErr != nil {
+			ui.RenderError(fmt.Sprintf("%v\nThe original index was tree %s, restore it with: git read-tree %s", restoreErr, originalTree, originalTree))
+		}
+		return err
+	}
+
+	return nil
+}
+
+// applyChanges unstages before it stages. The staged diff is relative to
+// HEAD, so the deselected hunks always reverse cleanly from the original
+// index. The unstaged diff is relative to that original index, so when the
+// same file lost a staged hunk its context may no longer match and the
+// selected hunks are merged in with the blobs they were made from.
+func (m selectModel) applyChanges(stage, unstage []patch.Unit, add []string) error {
+	if len(unstage) > 0 {
+		if err := git.ApplyCached(patch.Build(m.staged, unstage), true); err != nil {
+			return err
+		}
+	}
+
+	if len(stage) > 0 {
+		if err := git.ApplyCachedThreeWay(patch.Build(m.unstaged, stage)); err != nil {
+			return err
+		}
+	}
+
+	return git.AddPaths(add)
+}
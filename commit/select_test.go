@@ -0,0 +1,125 @@
+package commit
+
+import (
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/dinoDanic/diny/git"
+	"github.com/dinoDanic/diny/patch"
+)
+
+func runGit(t *testing.T, args ...string) string {
+	t.Helper()
+	output, err := exec.Command("git", args...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+	}
+	return string(output)
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// initRepo creates an empty repository in a temporary directory and makes it
+// the working directory of the test.
+func initRepo(t *testing.T) {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	runGit(t, "init", "-q")
+	runGit(t, "config", "user.email", "dino@example.com")
+	runGit(t, "config", "user.name", "Dino")
+}
+
+// loadSelectModel builds the selection view for the repository in the
+// current directory, as SelectHunks does.
+func loadSelectModel(t *testing.T) selectModel {
+	t.Helper()
+	staged, err := git.GetStagedPatch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	unstaged, err := git.GetUnstagedPatch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return newSelectModel(patch.Parse(staged), patch.Parse(unstaged), nil)
+}
+
+func TestApplySameFileStagedAndUnstaged(t *testing.T) {
+	initRepo(t)
+
+	lines := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n"
+	writeFile(t, "f.txt", lines)
+	runGit(t, "add", "f.txt")
+	runGit(t, "commit", "-q", "-m", "init")
+
+	writeFile(t, "f.txt", strings.Replace(lines, "3\n", "three\n", 1))
+	runGit(t, "add", "f.txt")
+	writeFile(t, "f.txt", strings.Replace(strings.Replace(lines, "3\n", "three\n", 1), "5\n", "five\n", 1))
+
+	// Unstage the staged hunk and stage the unstaged one of the same file,
+	// whose context still shows the staged change.
+	m := loadSelectModel(t)
+	for i := range m.items {
+		m.items[i].selected = m.items[i].section == unstagedSection
+	}
+	if err := m.apply(); err != nil {
+		t.Fatalf("apply() = %v", err)
+	}
+
+	want := strings.Replace(lines, "5\n", "five\n", 1)
+	if got := runGit(t, "show", ":f.txt"); got != want {
+		t.Errorf("index =\n%s\nwant\n%s", got, want)
+	}
+
+	// A selection that can not be applied leaves the index as it was.
+	writeFile(t, "f.txt", strings.Replace(want, "five\n", "FIVE\n", 1))
+	m = loadSelectModel(t)
+	for i := range m.items {
+		m.items[i].selected = m.items[i].section == unstagedSection
+	}
+	if err := m.apply(); err == nil {
+		t.Fatal("apply() = nil, want a conflict")
+	}
+	if got := runGit(t, "show", ":f.txt"); got != want {
+		t.Errorf("index after conflict =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestDiffPrefixConfig(t *testing.T) {
+	for _, option := range []string{"diff.noprefix", "diff.mnemonicPrefix"} {
+		t.Run(option, func(t *testing.T) {
+			initRepo(t)
+			runGit(t, "config", option, "true")
+
+			writeFile(t, "f.txt", "1\n")
+			runGit(t, "add", "f.txt")
+			runGit(t, "commit", "-q", "-m", "init")
+			writeFile(t, "f.txt", "one\n")
+			runGit(t, "add", "f.txt")
+			writeFile(t, "f.txt", "uno\n")
+
+			m := loadSelectModel(t)
+			for _, files := range [][]patch.File{m.staged, m.unstaged} {
+				if len(files) != 1 || files[0].Path != "f.txt" {
+					t.Errorf("patch files = %+v, want f.txt", files)
+				}
+			}
+
+			diff, err := GetDiff(DiffSource{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := diffPaths(string(diff)); !slices.Equal(got, []string{"f.txt"}) {
+				t.Errorf("diffPaths(GetDiff()) = %q, want [f.txt]", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,452 @@
+package commit
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/huh"
+	"github.com/dinoDanic/diny/config"
+	"github.com/dinoDanic/diny/git"
+	"github.com/dinoDanic/diny/history"
+	"github.com/dinoDanic/diny/patch"
+	"github.com/dinoDanic/diny/ui"
+	"github.com/spf13/cobra"
+)
+
+// splitGroup is one of the commits diny split will create.
+type splitGroup struct {
+	Units     []patch.Unit
+	Message   string
+	Generated string
+}
+
+// noiseFiles are left out of the diff sent for a group's message, like the
+// exclusions of GetDiff. They are still committed.
+var noiseFiles = []string{"*.lock", "package-lock.json", "yarn.lock"}
+var noiseDirs = []string{"node_modules", "dist", "build"}
+
+// Split proposes atomic commits for the staged changes and creates them one
+// by one, restoring the original index if anything fails.
+func Split(cmd *cobra.Command, args []string) {
+	fmt.Println()
+
+	if !ui.IsInteractive() {
+		ui.RenderError("diny split is interactive, run it in a terminal")
+		os.Exit(1)
+	}
+
+	staged, err := git.GetStagedPatch()
+	if err != nil {
+		ui.RenderError(err.Error())
+		os.Exit(1)
+	}
+
+	if strings.TrimSpace(staged) == "" {
+		ui.RenderWarning("No staged changes found. Stage files first with `git add`.")
+		os.Exit(0)
+	}
+
+	if _, err := git.GetHeadHash(); err != nil {
+		ui.RenderError("diny split needs an existing commit to build on, create the first commit with diny commit")
+		os.Exit(1)
+	}
+
+	files := patch.Parse(staged)
+	groups := proposeGroups(files)
+	userConfig, _ := config.Load()
+	examples := StyleExamples(staged, StyleExampleCount)
+
+	err = ui.WithSpinner(fmt.Sprintf("Generating messages for %d commits...", len(groups)), func() error {
+		return generateGroupMessages(files, groups, examples, userConfig)
+	})
+	if err != nil {
+		ui.RenderError(fmt.Sprintf("%v", err))
+		os.Exit(1)
+	}
+
+	for {
+		for i, group := range groups {
+			var paths []string
+			for _, unit := range group.Units {
+				paths = append(paths, unit.Label(files))
+			}
+			ui.RenderBox(fmt.Sprintf("Commit %d of %d", i+1, len(groups)), group.Message+"\n\n"+tuiMutedStyle.Render(strings.Join(paths, "\n")))
+		}
+
+		switch splitChoicePrompt(len(groups)) {
+		case "create":
+			if err := createSplitCommits(files, groups, userConfig); err != nil {
+				os.Exit(1)
+			}
+			return
+		case "adjust":
+			adjusted, ok, err := adjustGroups(files, groups)
+			if err != nil {
+				ui.RenderError(fmt.Sprintf("Error running prompt: %v", err))
+				os.Exit(1)
+			}
+			if !ok {
+				continue
+			}
+
+			groups = keepMessages(adjusted, groups)
+			err = ui.WithSpinner("Generating messages for the changed commits...", func() error {
+				return generateGroupMessages(files, groups, examples, userConfig)
+			})
+			if err != nil {
+				ui.RenderError(fmt.Sprintf("%v", err))
+				os.Exit(1)
+			}
+		case "edit":
+			i := selectGroupPrompt(groups)
+			edited, err := EditMessage(groups[i].Message)
+			if err != nil {
+				ui.RenderError(fmt.Sprintf("Error: %v", err))
+			} else if edited != "" {
+				groups[i].Message = edited
+			}
+		case "exit":
+			ui.RenderTitle("Bye!")
+			os.Exit(0)
+		}
+	}
+}
+
+// proposeGroups groups the units of files by concern. Documentation, CI
+// configuration and dependency manifests each become one commit. Code files
+// share a commit when their changes are coupled, a test with the file it
+// tests or a file using a name another one declares, so a feature spanning
+// directories stays together and unrelated edits in one directory do not.
+func proposeGroups(files []patch.File) []splitGroup {
+	fileKeys := groupKeys(files)
+
+	var keys []string
+	byKey := make(map[string]*splitGroup)
+
+	for _, unit := range patch.Units(files) {
+		key := fileKeys[unit.File]
+		if byKey[key] == nil {
+			keys = append(keys, key)
+			byKey[key] = &splitGroup{}
+		}
+		byKey[key].Units = append(byKey[key].Units, unit)
+	}
+
+	groups := make([]splitGroup, len(keys))
+	for i, key := range keys {
+		groups[i] = *byKey[key]
+	}
+	return groups
+}
+
+// groupKeys returns the group of every file: its area, or for code the path
+// of the first file it is coupled with.
+func groupKeys(files []patch.File) []string {
+	keys := make([]string, len(files))
+	root := make([]int, len(files))
+	find := func(i int) int {
+		for root[i] != i {
+			i = root[i]
+		}
+		return i
+	}
+
+	var code []int
+	names := make([]changedNames, len(files))
+	for i, file := range files {
+		root[i] = i
+		if area := groupArea(file.Path); area != "" {
+			keys[i] = area
+			continue
+		}
+		code = append(code, i)
+		names[i] = namesOf(file)
+	}
+
+	for x, a := range code {
+		for _, b := range code[x+1:] {
+			if coupled(files[a].Path, files[b].Path, names[a], names[b]) {
+				ra, rb := find(a), find(b)
+				root[max(ra, rb)] = min(ra, rb)
+			}
+		}
+	}
+
+	for _, i := range code {
+		keys[i] = "file:" + files[find(i)].Path
+	}
+	return keys
+}
+
+// groupArea returns the area of path that is committed on its own, or "" for
+// code.
+func groupArea(path string) string {
+	base := filepath.Base(path)
+	switch {
+	case strings.HasPrefix(path, ".github/") || strings.HasPrefix(path, ".gitlab-ci") || strings.HasPrefix(path, ".circleci/"):
+		return "ci"
+	case strings.HasPrefix(path, "docs/") || slices.Contains([]string{".md", ".mdx", ".rst"}, strings.ToLower(filepath.Ext(path))):
+		return "docs"
+	case slices.Contains(manifestFiles, base) || isLockFile(base):
+		return "deps"
+	default:
+		return ""
+	}
+}
+
+// manifestFiles declare dependencies, they are committed with the lock files.
+var manifestFiles = []string{"go.mod", "go.sum", "package.json", "Cargo.toml", "Cargo.lock", "requirements.txt", "pyproject.toml", "Gemfile", "Gemfile.lock"}
+
+// declaration matches the name a changed line declares in the common
+// languages, e.g. "func (c *Client) Refund(" or "export function refund(".
+var declaration = regexp.MustCompile(`\b(?:func|type|class|def|function|interface|struct|enum|trait|fn)\s+(?:\([^)]*\)\s*)?([A-Za-z_]\w*)`)
+
+var identifier = regexp.MustCompile(`[A-Za-z_]\w*`)
+
+// genericNames are declared by many unrelated files and couple nothing.
+var genericNames = []string{"init", "main", "String", "Error", "constructor", "toString", "render", "setUp", "tearDown"}
+
+// changedNames are the names in the changed lines of a file.
+type changedNames struct {
+	declared []string
+	used     map[string]bool
+}
+
+func namesOf(file patch.File) changedNames {
+	names := changedNames{used: make(map[string]bool)}
+	for _, hunk := range file.Hunks {
+		for _, line := range hunk.Lines {
+			if !strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "-") {
+				continue
+			}
+			for _, match := range declaration.FindAllStringSubmatch(line[1:], -1) {
+				if name := match[1]; len(name) >= 4 && !slices.Contains(genericNames, name) {
+					names.declared = append(names.declared, name)
+				}
+			}
+			for _, word := range identifier.FindAllString(line[1:], -1) {
+				names.used[word] = true
+			}
+		}
+	}
+	return names
+}
+
+// coupled reports whether the changes to two code files belong together: a
+// test and its subject, or one using a name the other declares and does not
+// declare itself.
+func coupled(pathA, pathB string, a, b changedNames) bool {
+	if subjectOf(pathA) == subjectOf(pathB) {
+		return true
+	}
+
+	uses := func(from, to changedNames) bool {
+		for _, name := range from.declared {
+			if to.used[name] && !slices.Contains(to.declared, name) {
+				return true
+			}
+		}
+		return false
+	}
+	return uses(a, b) || uses(b, a)
+}
+
+// subjectOf returns path without its extension and test markers, so
+// "api/refund_test.go" and "api/refund.go" have the same subject.
+func subjectOf(path string) string {
+	dir, base := filepath.Split(path)
+	base = strings.TrimSuffix(base, filepath.Ext(base))
+	for _, suffix := range []string{"_test", ".test", ".spec", "_spec"} {
+		base = strings.TrimSuffix(base, suffix)
+	}
+	return dir + strings.TrimPrefix(base, "test_")
+}
+
+func isNoise(path string) bool {
+	if isLockFile(filepath.Base(path)) {
+		return true
+	}
+	for _, dir := range noiseDirs {
+		if slices.Contains(strings.Split(filepath.Dir(path), "/"), dir) {
+			return true
+		}
+	}
+	return false
+}
+
+func isLockFile(name string) bool {
+	for _, pattern := range noiseFiles {
+		if matched, _ := filepath.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// keepMessages carries the messages of unchanged commits over to adjusted.
+func keepMessages(adjusted, previous []splitGroup) []splitGroup {
+	for i := range adjusted {
+		for _, group := range previous {
+			if slices.Equal(adjusted[i].Units, group.Units) {
+				adjusted[i].Message = group.Message
+				adjusted[i].Generated = group.Generated
+			}
+		}
+	}
+	return adjusted
+}
+
+// generateGroupMessages generates a message for every group that has none,
+// concurrently, in the style of examples, marking breaking API changes.
+func generateGroupMessages(files []patch.File, groups []splitGroup, examples []string, userConfig *config.UserConfig) error {
+	errs := make([]error, len(groups))
+
+	var wg sync.WaitGroup
+	for i := range groups {
+		if groups[i].Message != "" {
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			message, err := CreateCommitMessage(groupDiff(files, groups[i].Units), examples, userConfig)
+			if err == nil {
+				message = message.WithBreaking(DetectBreaking(patch.Build(files, groups[i].Units), DiffSource{}), userConfig)
+			}
+			groups[i].Message, groups[i].Generated, errs[i] = message.Text, message.Text, err
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// groupDiff is the diff of units sent to generate their message.
+func groupDiff(files []patch.File, units []patch.Unit) string {
+	var relevant []patch.Unit
+	for _, unit := range units {
+		if !isNoise(files[unit.File].Path) {
+			relevant = append(relevant, unit)
+		}
+	}
+
+	if len(relevant) == 0 {
+		relevant = units
+	}
+	return patch.Build(files, relevant)
+}
+
+// createSplitCommits stages and commits each group in order on top of HEAD.
+// If a patch does not apply or a commit fails, the commits made so far are
+// undone and the original index is restored.
+func createSplitCommits(files []patch.File, groups []splitGroup, userConfig *config.UserConfig) error {
+	originalHead, err := git.GetHeadHash()
+	if err != nil {
+		ui.RenderError(err.Error())
+		return err
+	}
+
+	originalTree, err := git.WriteTree()
+	if err != nil {
+		ui.RenderError(err.Error())
+		return err
+	}
+
+	restore := func(cause error) error {
+		if head, _ := git.GetHeadHash(); head != originalHead {
+			if err := git.ResetSoft(originalHead); err != nil {
+				ui.RenderError(fmt.Sprintf("%v\nThe original commit was %s", err, originalHead))
+			}
+		}
+		if err := git.ReadTree(originalTree); err != nil {
+			ui.RenderError(fmt.Sprintf("%v\nThe original index was tree %s, restore it with: git read-tree %s", err, originalTree, originalTree))
+			return cause
+		}
+		ui.RenderWarning("Restored the original staged changes, no commits were kept.")
+		return cause
+	}
+
+	if err := git.ReadTree("HEAD"); err != nil {
+		ui.RenderError(err.Error())
+		return restore(err)
+	}
+
+	for i, group := range groups {
+		if err := git.ApplyCached(patch.Build(files, group.Units), false); err != nil {
+			ui.RenderError(fmt.Sprintf("Failed to stage commit %d: %v", i+1, err))
+			return restore(err)
+		}
+
+		session := history.NewSession("split", groupDiff(files, group.Units), userConfig)
+		session.AddCandidates(group.Generated)
+
+		if err := createCommit(group.Message, userConfig, Options{Session: session}); err != nil {
+			return restore(err)
+		}
+	}
+
+	if tree, err := git.WriteTree(); err == nil && tree != originalTree {
+		ui.RenderWarning("The index differs from the original staged changes, a hook may have changed files. Check git status.")
+	}
+
+	return nil
+}
+
+func splitChoicePrompt(count int) string {
+	var choice string
+
+	err := huh.NewSelect[string]().
+		Title("🦕 What would you like to do next?").
+		Description("Select an option using arrow keys or j,k and press Enter").
+		Options(
+			huh.NewOption(fmt.Sprintf("Create these %d commits", count), "create"),
+			huh.NewOption("Adjust the grouping", "adjust"),
+			huh.NewOption("Edit a message in $EDITOR", "edit"),
+			huh.NewOption("Exit", "exit"),
+		).
+		Value(&choice).
+		Height(6).
+		Run()
+
+	if err != nil {
+		ui.RenderError(fmt.Sprintf("Error running prompt: %v", err))
+		os.Exit(1)
+	}
+
+	return choice
+}
+
+func selectGroupPrompt(groups []splitGroup) int {
+	var choice int
+
+	options := make([]huh.Option[int], len(groups))
+	for i, group := range groups {
+		subject, _, _ := strings.Cut(group.Message, "\n")
+		options[i] = huh.NewOption(fmt.Sprintf("%d. %s", i+1, subject), i)
+	}
+
+	err := huh.NewSelect[int]().
+		Title("🦕 Which message do you want to edit?").
+		Options(options...).
+		Value(&choice).
+		Height(len(options) + 2).
+		Run()
+
+	if err != nil {
+		ui.RenderError(fmt.Sprintf("Error running prompt: %v", err))
+		os.Exit(1)
+	}
+
+	return choice
+}
@@ -0,0 +1,138 @@
+package commit
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/dinoDanic/diny/git"
+	"github.com/dinoDanic/diny/patch"
+)
+
+// fileDiff returns the diff adding lines to path.
+func fileDiff(path string, lines ...string) string {
+	diff := fmt.Sprintf("diff --git a/%s b/%s\n--- a/%s\n+++ b/%s\n@@ -1 +1,%d @@\n", path, path, path, path, len(lines)+1)
+	diff += " context\n"
+	for _, line := range lines {
+		diff += "+" + line + "\n"
+	}
+	return diff
+}
+
+func TestProposeGroups(t *testing.T) {
+	tests := []struct {
+		name  string
+		diffs []string
+		want  [][]string
+	}{
+		{
+			"areas",
+			[]string{
+				fileDiff("README.md", "Refunds are supported."),
+				fileDiff(".github/workflows/ci.yml", "go-version: 1.25"),
+				fileDiff("go.mod", "require example.com/pay v1.2.0"),
+				fileDiff("go.sum", "example.com/pay v1.2.0 h1:abc="),
+				fileDiff("docs/guide.txt", "Refund a payment."),
+			},
+			[][]string{{"README.md", "docs/guide.txt"}, {".github/workflows/ci.yml"}, {"go.mod", "go.sum"}},
+		},
+		{
+			"feature across directories",
+			[]string{
+				fileDiff("api/refund.go", "func RefundOrder(id string) error {"),
+				fileDiff("cmd/refund.go", "return api.RefundOrder(args[0])"),
+			},
+			[][]string{{"api/refund.go", "cmd/refund.go"}},
+		},
+		{
+			"unrelated changes in one directory",
+			[]string{
+				fileDiff("api/refund.go", "func RefundOrder(id string) error {"),
+				fileDiff("api/health.go", "const healthPath = \"/healthz\""),
+			},
+			[][]string{{"api/refund.go"}, {"api/health.go"}},
+		},
+		{
+			"tests with their subject",
+			[]string{
+				fileDiff("api/refund.go", "// refunds are idempotent"),
+				fileDiff("web/Button.tsx", "export const label = 'Pay'"),
+				fileDiff("api/refund_test.go", "// covers retries"),
+				fileDiff("web/Button.test.tsx", "expect(label).toBe('Pay')"),
+			},
+			[][]string{{"api/refund.go", "api/refund_test.go"}, {"web/Button.tsx", "web/Button.test.tsx"}},
+		},
+		{
+			"generic names",
+			[]string{
+				fileDiff("api/refund.go", "func (r Refund) String() string {"),
+				fileDiff("api/order.go", "func (o Order) String() string {"),
+			},
+			[][]string{{"api/refund.go"}, {"api/order.go"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := patch.Parse(strings.Join(tt.diffs, ""))
+
+			var got [][]string
+			for _, group := range proposeGroups(files) {
+				var paths []string
+				for _, unit := range group.Units {
+					paths = append(paths, files[unit.File].Path)
+				}
+				got = append(got, paths)
+			}
+
+			if !slices.EqualFunc(got, tt.want, slices.Equal[[]string]) {
+				t.Errorf("proposeGroups() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateSplitCommitsRestoresOnFailure(t *testing.T) {
+	initRepo(t)
+
+	writeFile(t, "a.txt", "a\n")
+	writeFile(t, "b.txt", "b\n")
+	runGit(t, "add", ".")
+	runGit(t, "commit", "-q", "-m", "init")
+	head := runGit(t, "rev-parse", "HEAD")
+
+	// The second commit of the series is rejected by a hook
+	hook := filepath.Join(".git", "hooks", "commit-msg")
+	if err := os.WriteFile(hook, []byte("#!/bin/sh\n! grep -q second \"$1\"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	writeFile(t, "a.txt", "a\nchanged\n")
+	writeFile(t, "b.txt", "b\nchanged\n")
+	runGit(t, "add", ".")
+	tree := runGit(t, "write-tree")
+
+	staged, err := git.GetStagedPatch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := patch.Parse(staged)
+	groups := []splitGroup{
+		{Units: []patch.Unit{{File: 0, Hunk: -1}}, Message: "first"},
+		{Units: []patch.Unit{{File: 1, Hunk: -1}}, Message: "second"},
+	}
+
+	if err := createSplitCommits(files, groups, nil); err == nil {
+		t.Fatal("createSplitCommits() succeeded, want the hook failure")
+	}
+
+	if got := runGit(t, "rev-parse", "HEAD"); got != head {
+		t.Errorf("HEAD = %s, want the original %s", got, head)
+	}
+	if got := runGit(t, "write-tree"); got != tree {
+		t.Errorf("index tree = %s, want the original %s", got, tree)
+	}
+}
@@ -0,0 +1,186 @@
+package commit
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dinoDanic/diny/patch"
+)
+
+// splitModel lets the user move hunks between the proposed commits.
+type splitModel struct {
+	files   []patch.File
+	units   []patch.Unit
+	group   []int
+	titles  []string
+	count   int
+	cursor  int
+	preview bool
+	done    bool
+}
+
+func newSplitModel(files []patch.File, groups []splitGroup) splitModel {
+	m := splitModel{files: files, count: len(groups)}
+
+	for g, group := range groups {
+		subject, _, _ := strings.Cut(group.Message, "\n")
+		m.titles = append(m.titles, subject)
+		for _, unit := range group.Units {
+			m.units = append(m.units, unit)
+			m.group = append(m.group, g)
+		}
+	}
+
+	return m
+}
+
+// order returns the unit indices as displayed: by commit, then diff order.
+func (m splitModel) order() []int {
+	order := make([]int, len(m.units))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return m.group[order[a]] < m.group[order[b]]
+	})
+	return order
+}
+
+func (m splitModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m splitModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	order := m.order()
+	unit := order[m.cursor]
+
+	switch key.String() {
+	case "ctrl+c", "q", "esc":
+		return m, tea.Quit
+	case "enter":
+		m.done = true
+		return m, tea.Quit
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(order)-1 {
+			m.cursor++
+		}
+	case " ", "p":
+		m.preview = !m.preview
+	case "left", "h":
+		m.move(unit, m.group[unit]-1)
+	case "right", "l":
+		m.move(unit, m.group[unit]+1)
+	case "n":
+		m.move(unit, m.count)
+	default:
+		if n := key.String(); len(n) == 1 && n[0] >= '1' && n[0] <= '9' {
+			m.move(unit, int(n[0]-'1'))
+		}
+	}
+
+	return m, nil
+}
+
+// move puts unit into commit g, opening a new commit when g is one past the
+// last, and keeps the cursor on the moved unit.
+func (m *splitModel) move(unit, g int) {
+	if g < 0 || g > m.count {
+		return
+	}
+
+	if g == m.count {
+		m.count++
+		m.titles = append(m.titles, "New commit")
+	}
+	m.group[unit] = g
+
+	for i, u := range m.order() {
+		if u == unit {
+			m.cursor = i
+		}
+	}
+}
+
+func (m splitModel) View() string {
+	var b strings.Builder
+
+	b.WriteString(tuiTitleStyle.Render("🦕 Adjust the commits") + "\n")
+	b.WriteString(tuiMutedStyle.Render("↑/↓ select • ←/→ or 1-9 move to commit • n new commit • space preview • enter done • q cancel") + "\n\n")
+
+	order := m.order()
+	position := 0
+	for g := 0; g < m.count; g++ {
+		b.WriteString(tuiTitleStyle.Render(fmt.Sprintf("Commit %d: %s", g+1, m.titles[g])) + "\n")
+
+		empty := true
+		for ; position < len(order) && m.group[order[position]] == g; position++ {
+			empty = false
+			label := m.units[order[position]].Label(m.files)
+			if position == m.cursor {
+				b.WriteString(tuiCursorStyle.Render("  > "+label) + "\n")
+			} else {
+				b.WriteString("    " + label + "\n")
+			}
+		}
+
+		if empty {
+			b.WriteString(tuiMutedStyle.Render("    (empty, will be dropped)") + "\n")
+		}
+		b.WriteString("\n")
+	}
+
+	if m.preview && len(order) > 0 {
+		b.WriteString(renderPreview(m.units[order[m.cursor]].Lines(m.files)))
+	}
+
+	return b.String()
+}
+
+// groups returns the adjusted commits without the empty ones.
+func (m splitModel) groups() []splitGroup {
+	groups := make([]splitGroup, m.count)
+	for i, unit := range m.units {
+		groups[m.group[i]].Units = append(groups[m.group[i]].Units, unit)
+	}
+
+	var result []splitGroup
+	for _, group := range groups {
+		if len(group.Units) > 0 {
+			sort.Slice(group.Units, func(a, b int) bool {
+				if group.Units[a].File != group.Units[b].File {
+					return group.Units[a].File < group.Units[b].File
+				}
+				return group.Units[a].Hunk < group.Units[b].Hunk
+			})
+			result = append(result, group)
+		}
+	}
+
+	return result
+}
+
+// adjustGroups runs the grouping TUI. It returns false when the user cancels.
+func adjustGroups(files []patch.File, groups []splitGroup) ([]splitGroup, bool, error) {
+	final, err := tea.NewProgram(newSplitModel(files, groups)).Run()
+	if err != nil {
+		return nil, false, err
+	}
+
+	m := final.(splitModel)
+	if !m.done {
+		return groups, false, nil
+	}
+
+	return m.groups(), true, nil
+}
@@ -0,0 +1,102 @@
+package commit
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/dinoDanic/diny/config"
+	"github.com/dinoDanic/diny/conventional"
+	"github.com/dinoDanic/diny/git"
+)
+
+// ExtractTicket returns the ticket key found in branch using pattern.
+// The first capture group is preferred over the whole match.
+func ExtractTicket(pattern, branch string) (string, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", fmt.Errorf("invalid ticket pattern: %w", err)
+	}
+
+	matches := re.FindStringSubmatch(branch)
+	if len(matches) == 0 {
+		return "", nil
+	}
+
+	if len(matches) > 1 && matches[1] != "" {
+		return matches[1], nil
+	}
+
+	return matches[0], nil
+}
+
+// applyTicket places the ticket key from the current branch into the commit
+// message according to the configured ticket format.
+func applyTicket(commitMessage string, userConfig *config.UserConfig) string {
+	if userConfig == nil || userConfig.TicketPattern == "" {
+		return commitMessage
+	}
+
+	branch, err := git.GetCurrentBranch()
+	if err != nil {
+		return commitMessage
+	}
+
+	ticket, err := ExtractTicket(userConfig.TicketPattern, branch)
+	if err != nil || ticket == "" {
+		return commitMessage
+	}
+
+	return AddTicket(commitMessage, ticket, userConfig.TicketFormatOrDefault())
+}
+
+// AddTicket inserts ticket into commitMessage as a subject prefix, a
+// conventional scope or a Refs footer. Messages that already mention the
+// ticket are returned unchanged.
+func AddTicket(commitMessage, ticket string, format config.TicketFormat) string {
+	commitMessage = strings.TrimSpace(commitMessage)
+	if mentionsTicket(commitMessage, ticket) {
+		return commitMessage
+	}
+
+	subject, rest, _ := strings.Cut(commitMessage, "\n")
+
+	switch format {
+	case config.TicketScope:
+		header := conventional.Parse(subject)
+		if !header.Conventional {
+			return AddTicket(commitMessage, ticket, config.TicketPrefix)
+		}
+		if header.Scope == "" {
+			header.Scope = ticket
+		} else {
+			header.Scope += "," + ticket
+		}
+		subject = header.FormatHeader()
+	case config.TicketPrefix:
+		subject = ticket + " " + subject
+	default:
+		return appendFooter(commitMessage, "Refs: "+ticket)
+	}
+
+	if rest == "" {
+		return subject
+	}
+	return subject + "\n" + rest
+}
+
+// mentionsTicket reports whether message contains ticket as a whole word, so
+// PAY-1 is not found in PAY-12345.
+func mentionsTicket(message, ticket string) bool {
+	return regexp.MustCompile(`(?:^|\W)` + regexp.QuoteMeta(ticket) + `(?:\W|$)`).MatchString(message)
+}
+
+// appendFooter adds footer to the trailing footer block of commitMessage,
+// starting a new paragraph when the message has none.
+func appendFooter(commitMessage, footer string) string {
+	if len(conventional.Parse(commitMessage).Footers) > 0 {
+		return commitMessage + "\n" + footer
+	}
+
+	return commitMessage + "\n\n" + footer
+}
@@ -0,0 +1,54 @@
+package commit
+
+import (
+	"testing"
+
+	"github.com/dinoDanic/diny/config"
+)
+
+func TestExtractTicket(t *testing.T) {
+	tests := []struct {
+		pattern, branch, want string
+	}{
+		{`[A-Z]+-[0-9]+`, "feature/PAY-1234-refund-flow", "PAY-1234"},
+		{`^\w+/([A-Z]+-\d+)`, "bugfix/OPS-7-hotfix", "OPS-7"},
+		{`[A-Z]+-[0-9]+`, "main", ""},
+	}
+
+	for _, tt := range tests {
+		got, err := ExtractTicket(tt.pattern, tt.branch)
+		if err != nil {
+			t.Fatalf("ExtractTicket(%q, %q) returned error: %v", tt.pattern, tt.branch, err)
+		}
+		if got != tt.want {
+			t.Errorf("ExtractTicket(%q, %q) = %q, want %q", tt.pattern, tt.branch, got, tt.want)
+		}
+	}
+}
+
+func TestAddTicket(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		format  config.TicketFormat
+		want    string
+	}{
+		{"prefix", "feat: add refunds", config.TicketPrefix, "PAY-1 feat: add refunds"},
+		{"scope", "feat: add refunds\n\n- body", config.TicketScope, "feat(PAY-1): add refunds\n\n- body"},
+		{"existing scope", "fix(api)!: drop v1", config.TicketScope, "fix(api,PAY-1)!: drop v1"},
+		{"scope fallback", "Add refunds", config.TicketScope, "PAY-1 Add refunds"},
+		{"footer", "feat: add refunds", config.TicketFooter, "feat: add refunds\n\nRefs: PAY-1"},
+		{"footer block", "feat: x\n\nSigned-off-by: A <a@b.c>", config.TicketFooter, "feat: x\n\nSigned-off-by: A <a@b.c>\nRefs: PAY-1"},
+		{"already present", "PAY-1 feat: x", config.TicketFooter, "PAY-1 feat: x"},
+		{"already in scope", "feat(PAY-1): x", config.TicketPrefix, "feat(PAY-1): x"},
+		{"longer ticket in body", "feat: x\n\nFollows up on PAY-12345.", config.TicketFooter, "feat: x\n\nFollows up on PAY-12345.\n\nRefs: PAY-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AddTicket(tt.message, "PAY-1", tt.format); got != tt.want {
+				t.Errorf("AddTicket() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package commit
+
+import (
+	"github.com/dinoDanic/diny/config"
+	"github.com/dinoDanic/diny/git"
+)
+
+// applyTrailers appends the configured static trailers, the repository
+// co-author roster and, when enabled, a Signed-off-by trailer. Trailers the
+// message already carries are kept as they are.
+func applyTrailers(commitMessage string, userConfig *config.UserConfig) (string, error) {
+	trailers := collectTrailers(userConfig)
+	if len(trailers) == 0 {
+		return commitMessage, nil
+	}
+
+	return git.InterpretTrailers(commitMessage, trailers)
+}
+
+func collectTrailers(userConfig *config.UserConfig) []string {
+	var trailers []string
+
+	if userConfig != nil {
+		trailers = append(trailers, userConfig.Trailers...)
+	}
+
+	if pairs, err := config.LoadPairs(); err == nil {
+		for _, pair := range pairs {
+			trailers = append(trailers, "Co-authored-by: "+pair)
+		}
+	}
+
+	if userConfig != nil && userConfig.Signoff {
+		if identity, err := git.GetUserIdentity(); err == nil {
+			trailers = append(trailers, "Signed-off-by: "+identity)
+		}
+	}
+
+	return trailers
+}
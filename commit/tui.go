@@ -0,0 +1,40 @@
+package commit
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dinoDanic/diny/ui"
+)
+
+var (
+	tuiTitleStyle   = lipgloss.NewStyle().Foreground(ui.PrimaryForeground).Bold(true)
+	tuiMutedStyle   = lipgloss.NewStyle().Foreground(ui.MutedForeground)
+	tuiCursorStyle  = lipgloss.NewStyle().Foreground(ui.PrimaryForeground).Bold(true)
+	tuiAddedStyle   = lipgloss.NewStyle().Foreground(ui.SuccessForeground)
+	tuiRemovedStyle = lipgloss.NewStyle().Foreground(ui.ErrorForeground)
+)
+
+// maxPreviewLines keeps the hunk preview from pushing the list off screen.
+const maxPreviewLines = 15
+
+// renderPreview colors diff lines, showing at most maxPreviewLines.
+func renderPreview(lines []string) string {
+	if len(lines) > maxPreviewLines {
+		lines = append(lines[:maxPreviewLines:maxPreviewLines], "...")
+	}
+
+	var b strings.Builder
+	for _, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "+"):
+			line = tuiAddedStyle.Render(line)
+		case strings.HasPrefix(line, "-"):
+			line = tuiRemovedStyle.Render(line)
+		default:
+			line = tuiMutedStyle.Render(line)
+		}
+		b.WriteString("  " + line + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,142 @@
+package commit
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/huh"
+	"github.com/dinoDanic/diny/git"
+	"github.com/dinoDanic/diny/history"
+	"github.com/dinoDanic/diny/ui"
+	"github.com/spf13/cobra"
+)
+
+// Undo soft-resets HEAD when it is an unpushed commit made by diny, so its
+// changes are staged again, and keeps its message in the history.
+func Undo(cmd *cobra.Command, args []string) {
+	fmt.Println()
+
+	head, err := git.GetHeadHash()
+	if err != nil {
+		ui.RenderWarning("There is no commit to undo.")
+		os.Exit(1)
+	}
+
+	session, err := undoableSession(head)
+	if err != nil {
+		ui.RenderWarning(err.Error())
+		os.Exit(1)
+	}
+
+	message, err := git.GetCommitMessage("HEAD")
+	if err != nil {
+		ui.RenderError(err.Error())
+		os.Exit(1)
+	}
+
+	ui.RenderBox(fmt.Sprintf("Commit %s", shortCommit(head)), message)
+
+	yes, _ := cmd.Flags().GetBool("yes")
+	if !yes {
+		if !ui.IsInteractive() {
+			ui.RenderError("Refusing to undo the commit without --yes")
+			os.Exit(1)
+		}
+
+		err := huh.NewConfirm().
+			Title("🦕 Undo this commit and keep its changes staged?").
+			Affirmative("Yes").
+			Negative("No").
+			Value(&yes).
+			Run()
+		if err != nil {
+			ui.RenderError(fmt.Sprintf("Error running prompt: %v", err))
+			os.Exit(1)
+		}
+	}
+
+	if !yes {
+		return
+	}
+
+	if err := git.ResetSoft(session.Base); err != nil {
+		ui.RenderError(err.Error())
+		os.Exit(1)
+	}
+
+	diff, _ := GetStagedDiff()
+	undone := history.Session{
+		Time:       time.Now(),
+		Command:    "undo",
+		DiffHash:   history.HashDiff(string(diff)),
+		Config:     session.Config,
+		Candidates: []string{message},
+		Message:    message,
+		Commit:     head,
+		Base:       session.Base,
+		Outcome:    history.Undone,
+	}
+
+	// History is best effort, the reset already succeeded
+	if err := history.Append(undone); err != nil {
+		ui.RenderWarning(fmt.Sprintf("Undid the commit but could not save its message: %v", err))
+	} else {
+		ui.RenderBox("Message saved", "Edit and commit it again with:\n  diny history reuse 1\n\nOr print it with:\n  diny history reuse 1 --print")
+	}
+
+	ui.RenderSuccess(fmt.Sprintf("Undid %s, its changes are staged again", shortCommit(head)))
+}
+
+// undoableSession returns the history session that created head, with the
+// commit to return to in Base. It explains why head cannot be undone
+// otherwise.
+func undoableSession(head string) (history.Session, error) {
+	sessions, err := history.Load()
+	if err != nil {
+		return history.Session{}, fmt.Errorf("could not read the diny history: %w", err)
+	}
+
+	var session history.Session
+	found := false
+	for _, s := range sessions {
+		if s.Outcome == history.Committed && s.Commit == head {
+			session, found = s, true
+			break
+		}
+	}
+
+	if !found {
+		return history.Session{}, fmt.Errorf("HEAD %s was not created by diny in this repository, use git reset --soft HEAD^ if you are sure", shortCommit(head))
+	}
+
+	if git.IsPushed(head) {
+		return history.Session{}, fmt.Errorf("HEAD %s is already pushed to the upstream branch, undoing it would rewrite shared history", shortCommit(head))
+	}
+
+	remotes, err := git.RemoteBranchesContaining(head)
+	if err != nil {
+		return history.Session{}, err
+	}
+	if len(remotes) > 0 {
+		return history.Session{}, fmt.Errorf("HEAD %s is already pushed to %s, undoing it would rewrite shared history", shortCommit(head), strings.Join(remotes, ", "))
+	}
+
+	if session.Base == "" {
+		return history.Session{}, fmt.Errorf("HEAD %s is the first commit of the repository, there is nothing to return to", shortCommit(head))
+	}
+
+	if !git.IsCommit(session.Base) {
+		return history.Session{}, fmt.Errorf("the commit HEAD %s was made on no longer exists", shortCommit(head))
+	}
+
+	return session, nil
+}
+
+func shortCommit(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
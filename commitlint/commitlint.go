@@ -0,0 +1,375 @@
+package commitlint
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/dinoDanic/diny/conventional"
+	"gopkg.in/yaml.v3"
+)
+
+type Level int
+
+const (
+	Disabled Level = 0
+	Warning  Level = 1
+	Error    Level = 2
+)
+
+// Rule is a single commitlint rule in its [level, applicable, value] form.
+type Rule struct {
+	Level      Level
+	Applicable string
+	Value      interface{}
+}
+
+// Config holds the subset of commitlint rules diny understands.
+type Config struct {
+	Path  string
+	Rules map[string]Rule
+}
+
+type Violation struct {
+	Rule    string
+	Level   Level
+	Message string
+}
+
+func (v Violation) String() string {
+	marker := "✖"
+	if v.Level == Warning {
+		marker = "⚠"
+	}
+	return fmt.Sprintf("%s %s [%s]", marker, v.Message, v.Rule)
+}
+
+var configFiles = []string{
+	".commitlintrc",
+	".commitlintrc.json",
+	".commitlintrc.yaml",
+	".commitlintrc.yml",
+}
+
+// conventionalRules mirrors the rules of @commitlint/config-conventional that
+// diny can check, used when a config extends it.
+var conventionalRules = map[string]Rule{
+	"type-enum": {Error, "always", []interface{}{
+		"build", "chore", "ci", "docs", "feat", "fix", "perf", "refactor", "revert", "style", "test",
+	}},
+	"subject-case": {Error, "never", []interface{}{
+		"sentence-case", "start-case", "pascal-case", "upper-case",
+	}},
+	"type-empty":           {Error, "never", nil},
+	"subject-empty":        {Error, "never", nil},
+	"header-max-length":    {Error, "always", 100},
+	"body-max-line-length": {Error, "always", 100},
+}
+
+// headerPattern is the default header pattern of commitlint's conventional
+// parser. Headers that do not match it, e.g. with a leading emoji or ticket,
+// have no type, scope or subject.
+var headerPattern = regexp.MustCompile(`^(\w*)(?:\((.*)\))?!?: (.*)$`)
+
+// Load looks for a commitlint configuration in root. It returns nil when the
+// repository has none.
+func Load(root string) (*Config, error) {
+	for _, name := range configFiles {
+		path := filepath.Join(root, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		var raw map[string]interface{}
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+
+		return parseConfig(path, raw)
+	}
+
+	path := filepath.Join(root, "package.json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil
+	}
+
+	var pkg struct {
+		Commitlint map[string]interface{} `json:"commitlint"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil || pkg.Commitlint == nil {
+		return nil, nil
+	}
+
+	return parseConfig(path, pkg.Commitlint)
+}
+
+func parseConfig(path string, raw map[string]interface{}) (*Config, error) {
+	config := &Config{Path: path, Rules: map[string]Rule{}}
+
+	if extendsConventional(raw["extends"]) {
+		for name, rule := range conventionalRules {
+			config.Rules[name] = rule
+		}
+	}
+
+	rules, _ := raw["rules"].(map[string]interface{})
+	for name, value := range rules {
+		rule, err := parseRule(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rule %s in %s: %w", name, filepath.Base(path), err)
+		}
+		config.Rules[name] = rule
+	}
+
+	return config, nil
+}
+
+func extendsConventional(extends interface{}) bool {
+	var names []string
+	switch v := extends.(type) {
+	case string:
+		names = []string{v}
+	case []interface{}:
+		for _, name := range v {
+			if s, ok := name.(string); ok {
+				names = append(names, s)
+			}
+		}
+	}
+
+	for _, name := range names {
+		if strings.Contains(name, "config-conventional") {
+			return true
+		}
+	}
+	return false
+}
+
+func parseRule(value interface{}) (Rule, error) {
+	parts, ok := value.([]interface{})
+	if !ok || len(parts) == 0 {
+		return Rule{}, fmt.Errorf("expected [level, applicable, value]")
+	}
+
+	var rule Rule
+	switch level := parts[0].(type) {
+	case int:
+		rule.Level = Level(level)
+	case float64:
+		rule.Level = Level(level)
+	default:
+		return Rule{}, fmt.Errorf("level must be 0, 1 or 2")
+	}
+
+	rule.Applicable = "always"
+	if len(parts) > 1 {
+		if applicable, ok := parts[1].(string); ok {
+			rule.Applicable = applicable
+		}
+	}
+
+	if len(parts) > 2 {
+		rule.Value = parts[2]
+	}
+
+	return rule, nil
+}
+
+// Lint validates message against the configured rules.
+func (c *Config) Lint(message string) []Violation {
+	if c == nil {
+		return nil
+	}
+
+	commit := conventional.Parse(message)
+	header := commit.Header
+
+	var commitType, scope, subject string
+	if matches := headerPattern.FindStringSubmatch(header); matches != nil {
+		commitType, scope, subject = matches[1], matches[2], matches[3]
+	}
+
+	var body []string
+	if commit.Body != "" {
+		body = strings.Split(commit.Body, "\n")
+	}
+
+	var violations []Violation
+	add := func(name string, rule Rule, format string, args ...interface{}) {
+		violations = append(violations, Violation{Rule: name, Level: rule.Level, Message: fmt.Sprintf(format, args...)})
+	}
+
+	names := make([]string, 0, len(c.Rules))
+	for name := range c.Rules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		rule := c.Rules[name]
+		if rule.Level == Disabled {
+			continue
+		}
+		always := rule.Applicable != "never"
+
+		switch name {
+		case "type-enum":
+			allowed := stringList(rule.Value)
+			if commitType != "" && containsString(allowed, commitType) != always {
+				add(name, rule, "type %q must %sbe one of [%s]", commitType, negate(always), strings.Join(allowed, ", "))
+			}
+		case "type-empty":
+			if (commitType == "") != always {
+				add(name, rule, "type must %sbe empty", negate(always))
+			}
+		case "subject-empty":
+			if (subject == "") != always {
+				add(name, rule, "subject must %sbe empty", negate(always))
+			}
+		case "scope-enum":
+			allowed := stringList(rule.Value)
+			if scope == "" || len(allowed) == 0 {
+				continue
+			}
+			for _, s := range splitScope(scope) {
+				if containsString(allowed, s) != always {
+					add(name, rule, "scope %q must %sbe one of [%s]", s, negate(always), strings.Join(allowed, ", "))
+				}
+			}
+		case "subject-case":
+			if subject == "" {
+				continue
+			}
+			cases := stringList(rule.Value)
+			matched := false
+			for _, c := range cases {
+				if matchesCase(subject, c) {
+					matched = true
+					break
+				}
+			}
+			if matched != always {
+				add(name, rule, "subject must %sbe %s", negate(always), strings.Join(cases, ", "))
+			}
+		case "header-max-length":
+			if max := intValue(rule.Value); max > 0 && len([]rune(header)) > max {
+				add(name, rule, "header must not be longer than %d characters, current length is %d", max, len([]rune(header)))
+			}
+		case "body-max-line-length":
+			max := intValue(rule.Value)
+			if max <= 0 {
+				continue
+			}
+			for _, line := range body {
+				if len([]rune(line)) > max && !strings.Contains(line, "://") {
+					add(name, rule, "body's lines must not be longer than %d characters", max)
+					break
+				}
+			}
+		}
+	}
+
+	return violations
+}
+
+// HasErrors reports whether any violation is error level.
+func HasErrors(violations []Violation) bool {
+	for _, v := range violations {
+		if v.Level == Error {
+			return true
+		}
+	}
+	return false
+}
+
+// Format renders violations one per line.
+func Format(violations []Violation) string {
+	lines := make([]string, len(violations))
+	for i, v := range violations {
+		lines[i] = v.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+func negate(always bool) string {
+	if always {
+		return ""
+	}
+	return "not "
+}
+
+func splitScope(scope string) []string {
+	return strings.FieldsFunc(scope, func(r rune) bool {
+		return r == ',' || r == '/' || r == '\\'
+	})
+}
+
+func stringList(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		var out []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+func intValue(value interface{}) int {
+	switch v := value.(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	}
+	return 0
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesCase(s, c string) bool {
+	switch c {
+	case "lower-case", "lowercase":
+		return s == strings.ToLower(s)
+	case "upper-case", "uppercase":
+		return s == strings.ToUpper(s)
+	case "sentence-case", "sentencecase":
+		first := []rune(s)[0]
+		return string(first) == strings.ToUpper(string(first)) && s[len(string(first)):] == strings.ToLower(s[len(string(first)):])
+	case "start-case", "startcase":
+		for _, word := range strings.Fields(s) {
+			first := string([]rune(word)[0])
+			if first != strings.ToUpper(first) {
+				return false
+			}
+		}
+		return true
+	case "pascal-case", "pascalcase":
+		return regexp.MustCompile(`^[A-Z][a-zA-Z0-9]*$`).MatchString(s)
+	case "camel-case", "camelcase":
+		return regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`).MatchString(s)
+	case "kebab-case", "kebabcase":
+		return regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`).MatchString(s)
+	case "snake-case", "snakecase":
+		return regexp.MustCompile(`^[a-z0-9]+(_[a-z0-9]+)*$`).MatchString(s)
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package commitlint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadAndLint(t *testing.T) {
+	dir := t.TempDir()
+	rc := `extends: ["@commitlint/config-conventional"]
+rules:
+  scope-enum: [2, always, [api, ui]]
+  header-max-length: [2, always, 40]
+`
+	if err := os.WriteFile(filepath.Join(dir, ".commitlintrc.yaml"), []byte(rc), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if config == nil {
+		t.Fatal("expected config to be loaded")
+	}
+
+	tests := []struct {
+		message string
+		rules   []string
+	}{
+		{"feat(api): add refunds", nil},
+		{"feature(api): add refunds", []string{"type-enum"}},
+		{"fix(db): Add index", []string{"scope-enum", "subject-case"}},
+		{"fix: handle the extremely long header line here", []string{"header-max-length"}},
+		{"✨ feat: add refunds", []string{"subject-empty", "type-empty"}},
+		{"PAY-12 feat: add refunds", []string{"subject-empty", "type-empty"}},
+		{"Add refunds", []string{"subject-empty", "type-empty"}},
+		{"feat!: drop refunds", nil},
+	}
+
+	for _, tt := range tests {
+		violations := config.Lint(tt.message)
+		if len(violations) != len(tt.rules) {
+			t.Errorf("Lint(%q) = %v, want rules %v", tt.message, violations, tt.rules)
+			continue
+		}
+		for i, rule := range tt.rules {
+			if violations[i].Rule != rule {
+				t.Errorf("Lint(%q)[%d] = %s, want %s", tt.message, i, violations[i].Rule, rule)
+			}
+		}
+	}
+}
+
+func TestLoadPackageJSON(t *testing.T) {
+	dir := t.TempDir()
+	pkg := `{"name": "x", "commitlint": {"rules": {"type-enum": [2, "always", ["feat"]]}}}`
+	if err := os.WriteFile(filepath.Join(dir, "package.json"), []byte(pkg), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := Load(dir)
+	if err != nil || config == nil {
+		t.Fatalf("Load() = %v, %v", config, err)
+	}
+
+	if violations := config.Lint("fix: x"); !HasErrors(violations) {
+		t.Errorf("expected type-enum error, got %v", violations)
+	}
+}
+
+func TestLoadMissing(t *testing.T) {
+	config, err := Load(t.TempDir())
+	if err != nil || config != nil {
+		t.Errorf("Load() = %v, %v, want nil, nil", config, err)
+	}
+}
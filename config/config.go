@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
 
 	"github.com/charmbracelet/huh"
 	"github.com/dinoDanic/diny/git"
@@ -13,6 +15,10 @@ import (
 
 type Tone string
 type Length string
+type TicketFormat string
+type EmojiStyle string
+type EmojiPlacement string
+type Backend string
 
 const (
 	Professional Tone = "professional"
@@ -26,62 +32,225 @@ const (
 	Long   Length = "long"
 )
 
+const (
+	DefaultSubjectMaxLength = 72
+	DefaultBodyWrap         = 72
+	DefaultBulletStyle      = "-"
+	DefaultOllamaURL        = "http://127.0.0.1:11434"
+)
+
+const (
+	EmojiUnicode   EmojiStyle = "unicode"
+	EmojiShortcode EmojiStyle = "shortcode"
+)
+
+const (
+	EmojiBeforeType EmojiPlacement = "before-type"
+	EmojiAfterType  EmojiPlacement = "after-type"
+	EmojiEnd        EmojiPlacement = "end"
+)
+
+const (
+	BackendDiny   Backend = "diny"
+	BackendOllama Backend = "ollama"
+)
+
+const (
+	TicketPrefix TicketFormat = "prefix"
+	TicketScope  TicketFormat = "scope"
+	TicketFooter TicketFormat = "footer"
+)
+
 type UserConfig struct {
+	// Version is the config schema version, see CurrentVersion.
+	Version int `json:"version"`
+
 	UseConventional bool   `json:"useConventional"`
 	UseEmoji        bool   `json:"useEmoji"`
 	Tone            Tone   `json:"tone"`
 	Length          Length `json:"length"`
+
+	// EmojiMap overrides the gitmoji defaults per commit type, e.g.
+	// {"fix": "🚑️"}. Values may be unicode or :shortcode:.
+	EmojiMap       map[string]string `json:"emojiMap,omitempty"`
+	EmojiStyle     EmojiStyle        `json:"emojiStyle,omitempty"`
+	EmojiPlacement EmojiPlacement    `json:"emojiPlacement,omitempty"`
+
+	// TicketPattern is a regular expression matched against the current
+	// branch name. The first capture group (or the whole match) is used as
+	// the ticket key, e.g. `[A-Z]+-[0-9]+` for feature/PAY-1234-refund-flow.
+	TicketPattern string       `json:"ticketPattern,omitempty"`
+	TicketFormat  TicketFormat `json:"ticketFormat,omitempty"`
+
+	// SubjectMaxLength, BodyWrap and BulletStyle control the formatter that
+	// runs on every generated message. Zero values use the defaults.
+	SubjectMaxLength int    `json:"subjectMaxLength,omitempty"`
+	BodyWrap         int    `json:"bodyWrap,omitempty"`
+	BulletStyle      string `json:"bulletStyle,omitempty"`
+
+	// Types limits the conventional commit types accepted by diny lint.
+	Types []string `json:"types,omitempty"`
+
+	// SkipHooks commits with --no-verify, skipping pre-commit and commit-msg hooks.
+	SkipHooks bool `json:"skipHooks,omitempty"`
+
+	// Signoff appends a Signed-off-by trailer for the configured git user.
+	Signoff bool `json:"signoff,omitempty"`
+	// Trailers are static "Key: value" trailers added to every message.
+	Trailers []string `json:"trailers,omitempty"`
+
+	// Backend generates the messages: the diny server (default) or an
+	// Ollama server at OllamaURL.
+	Backend Backend `json:"backend,omitempty"`
+	// OllamaURL is the Ollama server the ollama backend sends the diff to,
+	// a local install unless set.
+	OllamaURL string `json:"ollamaUrl,omitempty"`
 }
 
+// Load returns the active configuration: the profile named by --profile,
+// otherwise the repository's .git/diny-config.json, otherwise the global
+// profile selected by rules or the default profile. It returns nil when none
+// of them exist.
 func Load() (*UserConfig, error) {
+	loadedFrom = ""
+
 	gitRoot, err := git.FindGitRoot()
 	if err != nil {
 		return nil, err
 	}
 
+	if selectedProfile != "" {
+		return loadProfile(gitRoot, selectedProfile)
+	}
+
 	configPath := filepath.Join(gitRoot, ".git", "diny-config.json")
 
 	config, err := tryLoadConfig(configPath)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return nil, nil
+			return handleProfile(loadProfile(gitRoot, ""))
 		}
 		return handleConfigError(configPath, err)
 	}
 
-	if !isValidConfig(config) {
-		return handleInvalidConfig(configPath, config)
+	if err := validateConfig(config); err != nil {
+		return handleInvalidConfig(configPath, &ConfigError{Path: configPath, Kind: InvalidConfig, Err: err})
 	}
 
+	loadedFrom = ".git/diny-config.json"
+	return config, nil
+}
+
+// handleProfile reports a problem with the profile selected by rules or the
+// default profile on stderr and returns it with a nil config, which callers
+// use as the defaults. Unlike the repository config it is never prompted
+// for, since profiles live outside the repository.
+func handleProfile(config *UserConfig, err error) (*UserConfig, error) {
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "diny: %v; using defaults\n", err)
+		return nil, err
+	}
 	return config, nil
 }
 
+// tryLoadConfig reads the config file, migrating older schema versions in
+// place. The original file is kept next to it as a .bak backup.
 func tryLoadConfig(configPath string) (*UserConfig, error) {
 	data, err := os.ReadFile(configPath)
 	if err != nil {
 		return nil, err
 	}
 
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, &ConfigError{Path: configPath, Kind: CorruptConfig, Err: err}
+	}
+
+	from, migrated, err := migrate(raw)
+	if err != nil {
+		return nil, &ConfigError{Path: configPath, Kind: UnsupportedVersion, Err: err}
+	}
+
+	if migrated {
+		data, err = json.Marshal(raw)
+		if err != nil {
+			return nil, &ConfigError{Path: configPath, Kind: CorruptConfig, Err: err}
+		}
+	}
+
 	var config UserConfig
 	if err := json.Unmarshal(data, &config); err != nil {
-		return nil, err
+		return nil, &ConfigError{Path: configPath, Kind: CorruptConfig, Err: err}
+	}
+
+	if migrated && validateConfig(&config) == nil {
+		if err := backupConfig(configPath); err == nil {
+			if err := writeConfig(configPath, config); err == nil {
+				fmt.Fprintf(os.Stderr, "diny: migrated %s from version %d to %d\n", configPath, from, CurrentVersion)
+			}
+		}
 	}
 
 	return &config, nil
 }
 
-func isValidConfig(config *UserConfig) bool {
+func validateConfig(config *UserConfig) error {
 	validTones := []Tone{Professional, Casual, Friendly}
 	if !contains(validTones, config.Tone) {
-		return false
+		return fmt.Errorf("invalid tone %q", config.Tone)
 	}
 
 	validLengths := []Length{Short, Normal, Long}
 	if !contains(validLengths, config.Length) {
-		return false
+		return fmt.Errorf("invalid length %q", config.Length)
+	}
+
+	if config.TicketPattern != "" {
+		if _, err := regexp.Compile(config.TicketPattern); err != nil {
+			return fmt.Errorf("invalid ticketPattern: %w", err)
+		}
+	}
+
+	for _, trailer := range config.Trailers {
+		if !strings.Contains(trailer, ":") {
+			return fmt.Errorf("invalid trailer %q, expected \"Key: value\"", trailer)
+		}
+	}
+
+	if config.SubjectMaxLength < 0 || config.BodyWrap < 0 {
+		return fmt.Errorf("subjectMaxLength and bodyWrap must not be negative")
+	}
+
+	validBullets := []string{"", "-", "*", "•"}
+	if !contains(validBullets, config.BulletStyle) {
+		return fmt.Errorf("invalid bulletStyle %q", config.BulletStyle)
 	}
 
-	return true
+	validBackends := []Backend{"", BackendDiny, BackendOllama}
+	if !contains(validBackends, config.Backend) {
+		return fmt.Errorf("invalid backend %q", config.Backend)
+	}
+
+	if config.OllamaURL != "" && !strings.HasPrefix(config.OllamaURL, "http://") && !strings.HasPrefix(config.OllamaURL, "https://") {
+		return fmt.Errorf("ollamaUrl must be an http or https URL, got %q", config.OllamaURL)
+	}
+
+	validEmojiStyles := []EmojiStyle{"", EmojiUnicode, EmojiShortcode}
+	if !contains(validEmojiStyles, config.EmojiStyle) {
+		return fmt.Errorf("invalid emojiStyle %q", config.EmojiStyle)
+	}
+
+	validEmojiPlacements := []EmojiPlacement{"", EmojiBeforeType, EmojiAfterType, EmojiEnd}
+	if !contains(validEmojiPlacements, config.EmojiPlacement) {
+		return fmt.Errorf("invalid emojiPlacement %q", config.EmojiPlacement)
+	}
+
+	validTicketFormats := []TicketFormat{"", TicketPrefix, TicketScope, TicketFooter}
+	if !contains(validTicketFormats, config.TicketFormat) {
+		return fmt.Errorf("invalid ticketFormat %q", config.TicketFormat)
+	}
+
+	return nil
 }
 
 func contains[T comparable](slice []T, item T) bool {
@@ -93,13 +262,26 @@ func contains[T comparable](slice []T, item T) bool {
 	return false
 }
 
+// handleConfigError reports a config that could not be read. Without a
+// terminal the error is logged to stderr and returned so callers fall back
+// to defaults instead of blocking on a prompt.
 func handleConfigError(configPath string, err error) (*UserConfig, error) {
+	if !ui.IsInteractive() {
+		fmt.Fprintf(os.Stderr, "diny: %v; using defaults\n", err)
+		return nil, err
+	}
+
 	ui.RenderError(fmt.Sprintf("Configuration file is corrupted: %v", err))
 	return promptConfigAction(configPath)
 }
 
-func handleInvalidConfig(configPath string, config *UserConfig) (*UserConfig, error) {
-	ui.RenderWarning("Invalid configuration values detected!")
+func handleInvalidConfig(configPath string, err *ConfigError) (*UserConfig, error) {
+	if !ui.IsInteractive() {
+		fmt.Fprintf(os.Stderr, "diny: %v; using defaults\n", err)
+		return nil, err
+	}
+
+	ui.RenderWarning(fmt.Sprintf("Invalid configuration values detected: %v", err.Err))
 	return promptConfigAction(configPath)
 }
 
@@ -125,10 +307,10 @@ func promptConfigAction(configPath string) (*UserConfig, error) {
 	case "fix":
 		return promptUserForValidConfig(configPath)
 	case "defaults":
-		if err := os.Remove(configPath); err != nil {
-			ui.RenderWarning(fmt.Sprintf("Could not delete config file: %v", err))
+		if err := os.Rename(configPath, configPath+".bak"); err != nil {
+			ui.RenderWarning(fmt.Sprintf("Could not move config file aside: %v", err))
 		} else {
-			ui.RenderTitle("Using defaults..")
+			ui.RenderTitle("Using defaults.. (old config kept as diny-config.json.bak)")
 		}
 		return nil, nil // Let caller use defaults
 	default:
@@ -206,6 +388,12 @@ func Save(config UserConfig) error {
 
 	configPath := filepath.Join(gitRoot, ".git", "diny-config.json")
 
+	return writeConfig(configPath, config)
+}
+
+func writeConfig(configPath string, config UserConfig) error {
+	config.Version = CurrentVersion
+
 	data, err := json.MarshalIndent(config, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
@@ -225,5 +413,84 @@ func PrintConfiguration(userConfig UserConfig) {
 		userConfig.UseConventional,
 		userConfig.Tone,
 		userConfig.Length)
+	if userConfig.UseEmoji {
+		content += fmt.Sprintf("\n• Emoji style: %s (%s)", userConfig.EmojiStyleOrDefault(), userConfig.EmojiPlacementOrDefault())
+	}
+	if userConfig.TicketPattern != "" {
+		content += fmt.Sprintf("\n• Ticket: %s (%s)", userConfig.TicketPattern, userConfig.TicketFormatOrDefault())
+	}
+	if userConfig.Signoff {
+		content += "\n• Signoff: true"
+	}
+	if len(userConfig.Trailers) > 0 {
+		content += fmt.Sprintf("\n• Trailers: %s", strings.Join(userConfig.Trailers, ", "))
+	}
 	ui.RenderBox("Configuration", content)
 }
+
+// TicketFormatOrDefault returns the configured ticket placement, falling back
+// to a Refs footer when none is set.
+func (c *UserConfig) TicketFormatOrDefault() TicketFormat {
+	if c.TicketFormat == "" {
+		return TicketFooter
+	}
+	return c.TicketFormat
+}
+
+// BackendOrDefault returns the configured backend, the diny server unless
+// another one is set.
+func (c *UserConfig) BackendOrDefault() Backend {
+	if c == nil || c.Backend == "" {
+		return BackendDiny
+	}
+	return c.Backend
+}
+
+// OllamaURLOrDefault returns the Ollama server the ollama backend uses,
+// falling back to a local install.
+func (c *UserConfig) OllamaURLOrDefault() string {
+	if c == nil || c.OllamaURL == "" {
+		return DefaultOllamaURL
+	}
+	return c.OllamaURL
+}
+
+// SubjectMaxLengthOrDefault returns the configured header length limit.
+func (c *UserConfig) SubjectMaxLengthOrDefault() int {
+	if c == nil || c.SubjectMaxLength == 0 {
+		return DefaultSubjectMaxLength
+	}
+	return c.SubjectMaxLength
+}
+
+// BodyWrapOrDefault returns the configured body wrap column.
+func (c *UserConfig) BodyWrapOrDefault() int {
+	if c == nil || c.BodyWrap == 0 {
+		return DefaultBodyWrap
+	}
+	return c.BodyWrap
+}
+
+// BulletStyleOrDefault returns the configured body bullet marker.
+func (c *UserConfig) BulletStyleOrDefault() string {
+	if c == nil || c.BulletStyle == "" {
+		return DefaultBulletStyle
+	}
+	return c.BulletStyle
+}
+
+// EmojiStyleOrDefault returns the configured emoji rendering.
+func (c *UserConfig) EmojiStyleOrDefault() EmojiStyle {
+	if c == nil || c.EmojiStyle == "" {
+		return EmojiUnicode
+	}
+	return c.EmojiStyle
+}
+
+// EmojiPlacementOrDefault returns where the emoji goes in the header.
+func (c *UserConfig) EmojiPlacementOrDefault() EmojiPlacement {
+	if c == nil || c.EmojiPlacement == "" {
+		return EmojiBeforeType
+	}
+	return c.EmojiPlacement
+}
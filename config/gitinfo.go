@@ -12,6 +12,7 @@ import (
 )
 
 type GitInfo struct {
+	RepoHost  string `json:"-"`
 	RepoName  string `json:"repoName"`
 	RepoOwner string `json:"repoOwner"`
 	RepoURL   string `json:"repoURL"`
@@ -61,35 +62,34 @@ func GetGitInfo() (*GitInfo, error) {
 		return nil, fmt.Errorf("no origin remote found in git config")
 	}
 
-	repoOwner, repoName, err := parseGitURL(originURL)
+	repoHost, repoOwner, repoName, err := parseGitURL(originURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse git URL: %w", err)
 	}
 
 	return &GitInfo{
+		RepoHost:  repoHost,
 		RepoName:  repoName,
 		RepoOwner: repoOwner,
 		RepoURL:   originURL,
 	}, nil
 }
 
-func parseGitURL(url string) (owner, repo string, err error) {
-	url = strings.TrimSuffix(url, ".git")
+// parseGitURL reads the host, owner and repository of a remote URL, either
+// a URL like https://host/owner/repo or ssh://git@host:2222/owner/repo, or
+// the scp-like form git@host:owner/repo.
+func parseGitURL(url string) (host, owner, repo string, err error) {
+	url = strings.TrimSuffix(strings.TrimSuffix(url, "/"), ".git")
 
-	httpsRegex := regexp.MustCompile(`https?://[^/]+/([^/]+)/([^/]+)`)
-	if matches := httpsRegex.FindStringSubmatch(url); len(matches) == 3 {
-		return matches[1], matches[2], nil
+	urlRegex := regexp.MustCompile(`^[a-z][a-z+]*://(?:[^@/]+@)?([^/:]+)(?::\d+)?/([^/]+)/([^/]+)`)
+	if matches := urlRegex.FindStringSubmatch(url); len(matches) == 4 {
+		return matches[1], matches[2], matches[3], nil
 	}
 
-	sshRegex := regexp.MustCompile(`git@[^:]+:([^/]+)/([^/]+)`)
-	if matches := sshRegex.FindStringSubmatch(url); len(matches) == 3 {
-		return matches[1], matches[2], nil
+	scpRegex := regexp.MustCompile(`^(?:[^@/]+@)?([^:/]+):([^/]+)/([^/]+)`)
+	if matches := scpRegex.FindStringSubmatch(url); len(matches) == 4 {
+		return matches[1], matches[2], matches[3], nil
 	}
 
-	gitRegex := regexp.MustCompile(`git://[^/]+/([^/]+)/([^/]+)`)
-	if matches := gitRegex.FindStringSubmatch(url); len(matches) == 3 {
-		return matches[1], matches[2], nil
-	}
-
-	return "", "", fmt.Errorf("unsupported git URL format: %s", url)
+	return "", "", "", fmt.Errorf("unsupported git URL format: %s", url)
 }
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dinoDanic/diny/conventional"
+)
+
+// HistoryStyle summarizes how a repository's existing commits are written.
+type HistoryStyle struct {
+	Total        int
+	Conventional int
+	Emoji        int
+	WithBody     int
+	BodyLines    int
+}
+
+// DetectStyle analyzes commit messages for conventional headers, emoji and
+// body usage.
+func DetectStyle(messages []string) HistoryStyle {
+	style := HistoryStyle{Total: len(messages)}
+
+	for _, message := range messages {
+		commit := conventional.Parse(message)
+		if commit.Conventional {
+			style.Conventional++
+		}
+		if conventional.HasEmoji(commit.Header) {
+			style.Emoji++
+		}
+		if commit.Body != "" {
+			style.WithBody++
+			style.BodyLines += len(strings.Split(commit.Body, "\n"))
+		}
+	}
+
+	return style
+}
+
+// Apply sets the conventional, emoji and length settings that match the
+// majority of the analyzed commits.
+func (s HistoryStyle) Apply(config *UserConfig) {
+	if s.Total == 0 {
+		return
+	}
+
+	config.UseConventional = s.Conventional*2 > s.Total
+	config.UseEmoji = s.Emoji*2 > s.Total
+
+	switch {
+	case s.WithBody*2 <= s.Total:
+		config.Length = Short
+	case s.BodyLines/s.WithBody > 4:
+		config.Length = Long
+	default:
+		config.Length = Normal
+	}
+}
+
+func (s HistoryStyle) String() string {
+	percent := func(n int) int {
+		if s.Total == 0 {
+			return 0
+		}
+		return n * 100 / s.Total
+	}
+
+	return fmt.Sprintf("• Commits analyzed: %d\n• Conventional: %d%%\n• Emoji: %d%%\n• With body: %d%%",
+		s.Total, percent(s.Conventional), percent(s.Emoji), percent(s.WithBody))
+}
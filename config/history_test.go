@@ -0,0 +1,82 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDetectStyle(t *testing.T) {
+	tests := []struct {
+		name     string
+		messages []string
+		want     HistoryStyle
+		apply    UserConfig
+	}{
+		{
+			"conventional",
+			[]string{
+				"feat(api): add refunds\n\n- store refund state\n- expose POST /refunds",
+				"fix: retry on timeout",
+				"chore!: drop node 16",
+			},
+			HistoryStyle{Total: 3, Conventional: 3, WithBody: 1, BodyLines: 2},
+			UserConfig{UseConventional: true, Length: Short},
+		},
+		{
+			"emoji prefixed",
+			[]string{
+				"✨ feat: add refunds",
+				"🐛 fix: retry on timeout\n\nThe client gave up after the first attempt.",
+				":memo: docs: describe refunds",
+				"Merge branch 'main'",
+			},
+			HistoryStyle{Total: 4, Conventional: 3, Emoji: 3, WithBody: 1, BodyLines: 1},
+			UserConfig{UseConventional: true, UseEmoji: true, Length: Short},
+		},
+		{
+			"plain",
+			[]string{
+				"Add refunds\n\nStores the refund state and exposes it over HTTP.\nRefunds are idempotent.",
+				"Retry on timeout\n\nThe client gave up after the first attempt.",
+				"Update README",
+			},
+			HistoryStyle{Total: 3, WithBody: 2, BodyLines: 3},
+			UserConfig{Length: Normal},
+		},
+		{
+			"long bodies",
+			[]string{"Rework the payment flow\n\n- a\n- b\n- c\n- d\n- e"},
+			HistoryStyle{Total: 1, WithBody: 1, BodyLines: 5},
+			UserConfig{Length: Long},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DetectStyle(tt.messages)
+			if got != tt.want {
+				t.Errorf("DetectStyle() = %+v, want %+v", got, tt.want)
+			}
+
+			var applied UserConfig
+			got.Apply(&applied)
+			if applied.UseConventional != tt.apply.UseConventional || applied.UseEmoji != tt.apply.UseEmoji || applied.Length != tt.apply.Length {
+				t.Errorf("Apply() = conventional %v, emoji %v, length %q, want %v, %v, %q",
+					applied.UseConventional, applied.UseEmoji, applied.Length, tt.apply.UseConventional, tt.apply.UseEmoji, tt.apply.Length)
+			}
+		})
+	}
+}
+
+func TestDetectStyleEmpty(t *testing.T) {
+	style := DetectStyle(nil)
+	if style != (HistoryStyle{}) {
+		t.Errorf("DetectStyle(nil) = %+v, want the zero style", style)
+	}
+
+	applied := Default()
+	style.Apply(&applied)
+	if !reflect.DeepEqual(applied, Default()) {
+		t.Errorf("Apply() of an empty history changed the config to %+v", applied)
+	}
+}
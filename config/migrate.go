@@ -0,0 +1,93 @@
+package config
+
+import (
+	"fmt"
+	"os"
+)
+
+// CurrentVersion is the config schema version written by this diny.
+// Files without a version field are version 1.
+const CurrentVersion = 2
+
+type ConfigErrorKind string
+
+const (
+	CorruptConfig      ConfigErrorKind = "corrupt"
+	InvalidConfig      ConfigErrorKind = "invalid"
+	UnsupportedVersion ConfigErrorKind = "unsupported-version"
+)
+
+// ConfigError describes a config file diny could not use.
+type ConfigError struct {
+	Path string
+	Kind ConfigErrorKind
+	Err  error
+}
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("%s config %s: %v", e.Kind, e.Path, e.Err)
+}
+
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// Default returns the configuration used when nothing else is set.
+func Default() UserConfig {
+	return UserConfig{
+		Version: CurrentVersion,
+		Tone:    Casual,
+		Length:  Short,
+	}
+}
+
+// migrations upgrade the raw config from the version they are keyed by to
+// the next one.
+var migrations = map[int]func(raw map[string]interface{}){
+	1: migrateV1,
+}
+
+// migrateV1 fills in the tone and length that early releases did not
+// always write, instead of treating those files as invalid.
+func migrateV1(raw map[string]interface{}) {
+	defaults := Default()
+
+	if tone, _ := raw["tone"].(string); tone == "" {
+		raw["tone"] = string(defaults.Tone)
+	}
+	if length, _ := raw["length"].(string); length == "" {
+		raw["length"] = string(defaults.Length)
+	}
+}
+
+// migrate upgrades raw to CurrentVersion. It returns the original version
+// and whether anything changed.
+func migrate(raw map[string]interface{}) (int, bool, error) {
+	version := 1
+	if v, ok := raw["version"].(float64); ok {
+		version = int(v)
+	}
+	from := version
+
+	if version > CurrentVersion {
+		return from, false, fmt.Errorf("config version %d is newer than this diny supports (%d), please run diny update", version, CurrentVersion)
+	}
+
+	for version < CurrentVersion {
+		if migration, ok := migrations[version]; ok {
+			migration(raw)
+		}
+		version++
+	}
+
+	raw["version"] = version
+	return from, from != version, nil
+}
+
+func backupConfig(configPath string) error {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(configPath+".bak", data, 0644)
+}
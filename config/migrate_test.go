@@ -0,0 +1,67 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMigrate(t *testing.T) {
+	tests := []struct {
+		name        string
+		raw         map[string]interface{}
+		want        map[string]interface{}
+		wantFrom    int
+		wantChanged bool
+	}{
+		{
+			"v1 without tone and length",
+			map[string]interface{}{"useEmoji": true},
+			map[string]interface{}{"useEmoji": true, "tone": "casual", "length": "short", "version": 2},
+			1, true,
+		},
+		{
+			"v1 keeps its tone and length",
+			map[string]interface{}{"version": float64(1), "tone": "professional", "length": "long"},
+			map[string]interface{}{"version": 2, "tone": "professional", "length": "long"},
+			1, true,
+		},
+		{
+			"v1 with an empty tone",
+			map[string]interface{}{"tone": "", "length": "long"},
+			map[string]interface{}{"version": 2, "tone": "casual", "length": "long"},
+			1, true,
+		},
+		{
+			"current version",
+			map[string]interface{}{"version": float64(2), "length": "long"},
+			map[string]interface{}{"version": 2, "length": "long"},
+			2, false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, changed, err := migrate(tt.raw)
+			if err != nil {
+				t.Fatalf("migrate() returned error: %v", err)
+			}
+			if from != tt.wantFrom || changed != tt.wantChanged {
+				t.Errorf("migrate() = %d, %v, want %d, %v", from, changed, tt.wantFrom, tt.wantChanged)
+			}
+			if !reflect.DeepEqual(tt.raw, tt.want) {
+				t.Errorf("migrated config = %v, want %v", tt.raw, tt.want)
+			}
+		})
+	}
+}
+
+func TestMigrateNewerVersion(t *testing.T) {
+	raw := map[string]interface{}{"version": float64(CurrentVersion + 1)}
+
+	if _, _, err := migrate(raw); err == nil {
+		t.Errorf("migrate() of version %d succeeded, want an error", CurrentVersion+1)
+	}
+	if raw["version"] != float64(CurrentVersion+1) {
+		t.Errorf("migrate() changed the version of a newer config to %v", raw["version"])
+	}
+}
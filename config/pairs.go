@@ -0,0 +1,128 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dinoDanic/diny/git"
+)
+
+// Pairs is the per-repository co-author roster, stored as "Name <email>" entries.
+type Pairs []string
+
+func pairsPath() (string, error) {
+	gitRoot, err := git.FindGitRoot()
+	if err != nil {
+		return "", fmt.Errorf("not in a git repository: %w", err)
+	}
+
+	return filepath.Join(gitRoot, ".git", "diny-pairs.json"), nil
+}
+
+// LoadPairs reads the co-author roster. A missing file is an empty roster.
+func LoadPairs() (Pairs, error) {
+	path, err := pairsPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Pairs{}, nil
+		}
+		return nil, err
+	}
+
+	var pairs Pairs
+	if err := json.Unmarshal(data, &pairs); err != nil {
+		return nil, fmt.Errorf("failed to parse co-author roster: %w", err)
+	}
+
+	return pairs, nil
+}
+
+// SavePairs writes the co-author roster, removing the file when it is empty.
+func SavePairs(pairs Pairs) error {
+	path, err := pairsPath()
+	if err != nil {
+		return err
+	}
+
+	if len(pairs) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove co-author roster: %w", err)
+		}
+		return nil
+	}
+
+	data, err := json.MarshalIndent(pairs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal co-author roster: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write co-author roster: %w", err)
+	}
+
+	return nil
+}
+
+// Add appends coAuthor unless an entry with the same email already exists.
+// Entries sharing only a name are different people and both kept.
+func (p Pairs) Add(coAuthor string) (Pairs, bool) {
+	email := pairEmail(strings.ToLower(coAuthor))
+	for _, pair := range p {
+		if pairEmail(strings.ToLower(pair)) == email {
+			return p, false
+		}
+	}
+	return append(p, coAuthor), true
+}
+
+// Remove drops the entry matching query by full entry, name or email.
+func (p Pairs) Remove(query string) (Pairs, bool) {
+	i := p.index(query)
+	if i < 0 {
+		return p, false
+	}
+	return append(p[:i], p[i+1:]...), true
+}
+
+func (p Pairs) index(query string) int {
+	query = strings.ToLower(strings.TrimSpace(query))
+	queryEmail := pairEmail(query)
+
+	for i, pair := range p {
+		entry := strings.ToLower(pair)
+		name := strings.TrimSpace(strings.Split(entry, "<")[0])
+		email := pairEmail(entry)
+
+		if entry == query || name == query || email == query || (queryEmail != "" && email == queryEmail) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func pairEmail(entry string) string {
+	start := strings.Index(entry, "<")
+	end := strings.LastIndex(entry, ">")
+	if start < 0 || end <= start {
+		return ""
+	}
+	return strings.TrimSpace(entry[start+1 : end])
+}
+
+// ValidatePair checks that coAuthor is in "Name <email>" form.
+func ValidatePair(coAuthor string) error {
+	name := strings.TrimSpace(strings.Split(coAuthor, "<")[0])
+	if name == "" || pairEmail(coAuthor) == "" || !strings.HasSuffix(strings.TrimSpace(coAuthor), ">") {
+		return fmt.Errorf("co-author must look like \"Name <email>\"")
+	}
+	return nil
+}
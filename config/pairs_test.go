@@ -0,0 +1,71 @@
+package config
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPairsAdd(t *testing.T) {
+	roster := Pairs{"Ana Lee <ana@example.com>"}
+
+	tests := []struct {
+		name     string
+		coAuthor string
+		want     Pairs
+		added    bool
+	}{
+		{"new", "Bo Chen <bo@example.com>", Pairs{"Ana Lee <ana@example.com>", "Bo Chen <bo@example.com>"}, true},
+		{"same email", "Ana Lee <ana@example.com>", roster, false},
+		{"same email other case and name", "Ana L. <ANA@example.com>", roster, false},
+		{"same name other email", "Ana Lee <ana.lee@work.example>", Pairs{"Ana Lee <ana@example.com>", "Ana Lee <ana.lee@work.example>"}, true},
+	}
+
+	for _, tt := range tests {
+		got, added := slices.Clone(roster).Add(tt.coAuthor)
+		if !slices.Equal(got, tt.want) || added != tt.added {
+			t.Errorf("%s: Add(%q) = %q, %v, want %q, %v", tt.name, tt.coAuthor, got, added, tt.want, tt.added)
+		}
+	}
+}
+
+func TestPairsRemove(t *testing.T) {
+	roster := Pairs{"Ana Lee <ana@example.com>", "Bo Chen <bo@example.com>"}
+
+	tests := []struct {
+		query   string
+		want    Pairs
+		removed bool
+	}{
+		{"Ana Lee <ana@example.com>", Pairs{"Bo Chen <bo@example.com>"}, true},
+		{"bo chen", Pairs{"Ana Lee <ana@example.com>"}, true},
+		{"BO@example.com", Pairs{"Ana Lee <ana@example.com>"}, true},
+		{"Someone <bo@example.com>", Pairs{"Ana Lee <ana@example.com>"}, true},
+		{"Cy", roster, false},
+	}
+
+	for _, tt := range tests {
+		got, removed := slices.Clone(roster).Remove(tt.query)
+		if !slices.Equal(got, tt.want) || removed != tt.removed {
+			t.Errorf("Remove(%q) = %q, %v, want %q, %v", tt.query, got, removed, tt.want, tt.removed)
+		}
+	}
+}
+
+func TestValidatePair(t *testing.T) {
+	tests := []struct {
+		coAuthor string
+		valid    bool
+	}{
+		{"Ana Lee <ana@example.com>", true},
+		{"Ana Lee", false},
+		{"<ana@example.com>", false},
+		{"Ana Lee <>", false},
+		{"Ana Lee <ana@example.com> extra", false},
+	}
+
+	for _, tt := range tests {
+		if err := ValidatePair(tt.coAuthor); (err == nil) != tt.valid {
+			t.Errorf("ValidatePair(%q) = %v, want valid %v", tt.coAuthor, err, tt.valid)
+		}
+	}
+}
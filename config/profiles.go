@@ -0,0 +1,219 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/dinoDanic/diny/git"
+)
+
+// GlobalConfig is the user-wide config holding named profiles and the rules
+// that select them.
+type GlobalConfig struct {
+	Version        int                        `json:"version"`
+	DefaultProfile string                     `json:"defaultProfile,omitempty"`
+	Profiles       map[string]json.RawMessage `json:"profiles"`
+	Rules          []ProfileRule              `json:"rules,omitempty"`
+}
+
+// ProfileRule selects Profile when every non-empty field matches. Host,
+// Owner and Branch are globs; Path is a glob on the repository root where a
+// trailing /** matches everything below it and ~ is the home directory.
+type ProfileRule struct {
+	Profile string `json:"profile"`
+	Host    string `json:"host,omitempty"`
+	Owner   string `json:"owner,omitempty"`
+	Path    string `json:"path,omitempty"`
+	Branch  string `json:"branch,omitempty"`
+}
+
+var selectedProfile string
+
+// loadedFrom describes where the last Load found its configuration.
+var loadedFrom string
+
+// SelectProfile forces Load to use the named global profile, as set by
+// --profile. It fails when the profile does not exist or is invalid, so a
+// typo never falls back to other settings.
+func SelectProfile(name string) error {
+	global, err := LoadGlobal()
+	if err != nil {
+		return err
+	}
+	if _, err := global.Profile(name); err != nil {
+		return err
+	}
+
+	selectedProfile = name
+	return nil
+}
+
+// Source describes where the configuration returned by the last Load came from.
+func Source() string {
+	return loadedFrom
+}
+
+// GlobalConfigPath returns the location of the user-wide config file.
+func GlobalConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find user config directory: %w", err)
+	}
+	return filepath.Join(dir, "diny", "config.json"), nil
+}
+
+// LoadGlobal reads the user-wide config. A missing file is an empty config.
+func LoadGlobal() (*GlobalConfig, error) {
+	configPath, err := GlobalConfigPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &GlobalConfig{Version: CurrentVersion}, nil
+		}
+		return nil, err
+	}
+
+	var global GlobalConfig
+	if err := json.Unmarshal(data, &global); err != nil {
+		return nil, &ConfigError{Path: configPath, Kind: CorruptConfig, Err: err}
+	}
+
+	if global.Version > CurrentVersion {
+		return nil, &ConfigError{Path: configPath, Kind: UnsupportedVersion,
+			Err: fmt.Errorf("config version %d is newer than this diny supports (%d), please run diny update", global.Version, CurrentVersion)}
+	}
+
+	return &global, nil
+}
+
+// Profile returns the named profile on top of the defaults.
+func (g *GlobalConfig) Profile(name string) (*UserConfig, error) {
+	raw, ok := g.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("profile %q not found", name)
+	}
+
+	profile := Default()
+	if err := json.Unmarshal(raw, &profile); err != nil {
+		return nil, fmt.Errorf("profile %q: %w", name, err)
+	}
+
+	if err := validateConfig(&profile); err != nil {
+		return nil, fmt.Errorf("profile %q: %w", name, err)
+	}
+
+	return &profile, nil
+}
+
+// MatchProfile returns the profile of the first rule matching the current
+// repository, falling back to the default profile.
+func (g *GlobalConfig) MatchProfile(gitRoot string) string {
+	var info GitInfo
+	if gitInfo, err := GetGitInfo(); err == nil {
+		info = *gitInfo
+	}
+
+	branch, _ := git.GetCurrentBranch()
+
+	for _, rule := range g.Rules {
+		if rule.matches(info, gitRoot, branch) {
+			return rule.Profile
+		}
+	}
+
+	return g.DefaultProfile
+}
+
+func (r ProfileRule) matches(info GitInfo, gitRoot, branch string) bool {
+	if r.Host == "" && r.Owner == "" && r.Path == "" && r.Branch == "" {
+		return false
+	}
+
+	if r.Host != "" && !globMatch(strings.ToLower(r.Host), strings.ToLower(info.RepoHost)) {
+		return false
+	}
+
+	if r.Owner != "" && !globMatch(strings.ToLower(r.Owner), strings.ToLower(info.RepoOwner)) {
+		return false
+	}
+
+	if r.Branch != "" && !globMatch(r.Branch, branch) {
+		return false
+	}
+
+	if r.Path != "" && !pathMatch(r.Path, gitRoot) {
+		return false
+	}
+
+	return true
+}
+
+func globMatch(pattern, value string) bool {
+	if value == "" {
+		return false
+	}
+	matched, err := path.Match(pattern, value)
+	return err == nil && matched
+}
+
+func pathMatch(pattern, dir string) bool {
+	if strings.HasPrefix(pattern, "~") {
+		if home, err := os.UserHomeDir(); err == nil {
+			pattern = filepath.Join(home, pattern[1:])
+		}
+	}
+
+	pattern = filepath.ToSlash(filepath.Clean(pattern))
+	dir = filepath.ToSlash(filepath.Clean(dir))
+
+	if prefix, ok := strings.CutSuffix(pattern, "/**"); ok {
+		return dir == prefix || strings.HasPrefix(dir, prefix+"/") || globPrefixMatch(prefix, dir)
+	}
+
+	return globMatch(pattern, dir)
+}
+
+// globPrefixMatch reports whether some leading part of dir matches pattern.
+func globPrefixMatch(pattern, dir string) bool {
+	parts := strings.Split(dir, "/")
+	for i := range parts {
+		if globMatch(pattern, strings.Join(parts[:i+1], "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// loadProfile resolves the profile to use when the repository has no config
+// of its own: the one named by --profile, a matching rule or the default.
+func loadProfile(gitRoot string, name string) (*UserConfig, error) {
+	global, err := LoadGlobal()
+	if err != nil {
+		return nil, err
+	}
+
+	if name == "" {
+		name = global.MatchProfile(gitRoot)
+	}
+	if name == "" {
+		return nil, nil
+	}
+
+	profile, err := global.Profile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	configPath, _ := GlobalConfigPath()
+	loadedFrom = fmt.Sprintf("profile %q (%s)", name, configPath)
+
+	return profile, nil
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestProfileRuleMatches(t *testing.T) {
+	info := GitInfo{RepoHost: "gitlab.example.com", RepoOwner: "Payments"}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		rule    ProfileRule
+		gitRoot string
+		want    bool
+	}{
+		{"empty rule", ProfileRule{Profile: "work"}, "/src/api", false},
+		{"host", ProfileRule{Host: "*.example.com"}, "/src/api", true},
+		{"host case", ProfileRule{Host: "GITLAB.example.com"}, "/src/api", true},
+		{"other host", ProfileRule{Host: "github.com"}, "/src/api", false},
+		{"owner", ProfileRule{Owner: "payments"}, "/src/api", true},
+		{"host and other owner", ProfileRule{Host: "*.example.com", Owner: "platform"}, "/src/api", false},
+		{"branch", ProfileRule{Branch: "feature/*"}, "/src/api", true},
+		{"other branch", ProfileRule{Branch: "release/*"}, "/src/api", false},
+		{"path", ProfileRule{Path: "/src/api"}, "/src/api", true},
+		{"path below", ProfileRule{Path: "/src/**"}, "/src/work/api", true},
+		{"path itself", ProfileRule{Path: "/src/**"}, "/src", true},
+		{"path glob below", ProfileRule{Path: "/src/*/**"}, "/src/work/api", true},
+		{"path sibling", ProfileRule{Path: "/src/**"}, "/srcs/api", false},
+		{"path home", ProfileRule{Path: "~/work/**"}, filepath.Join(home, "work", "api"), true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.rule.matches(info, tt.gitRoot, "feature/refunds"); got != tt.want {
+			t.Errorf("%s: %+v matches = %v, want %v", tt.name, tt.rule, got, tt.want)
+		}
+	}
+}
+
+func TestMatchProfile(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "feature/refunds"},
+		{"-c", "user.name=Dino", "-c", "user.email=dino@example.com", "commit", "-q", "--allow-empty", "-m", "init"},
+		{"remote", "add", "origin", "ssh://git@git.example.com:2222/payments/api.git"},
+	} {
+		if output, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, output)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		rules []ProfileRule
+		want  string
+	}{
+		{"no rules", nil, "personal"},
+		{"owner", []ProfileRule{{Profile: "work", Owner: "payments"}}, "work"},
+		{"port is not the owner", []ProfileRule{{Profile: "work", Owner: "2222"}}, "personal"},
+		{"first match wins", []ProfileRule{{Profile: "oss", Host: "github.com"}, {Profile: "work", Branch: "feature/*"}, {Profile: "other", Host: "git.example.com"}}, "work"},
+	}
+
+	for _, tt := range tests {
+		global := GlobalConfig{DefaultProfile: "personal", Rules: tt.rules}
+		if got := global.MatchProfile(dir); got != tt.want {
+			t.Errorf("%s: MatchProfile() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
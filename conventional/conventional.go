@@ -0,0 +1,252 @@
+package conventional
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultTypes are the commit types allowed when no list is configured.
+var DefaultTypes = []string{
+	"build", "chore", "ci", "docs", "feat", "fix", "perf", "refactor", "revert", "style", "test",
+}
+
+// Emoji positions within the header.
+const (
+	EmojiBeforeType = "before-type"
+	EmojiAfterType  = "after-type"
+	EmojiEnd        = "end"
+)
+
+type Footer struct {
+	Token string `json:"token"`
+	Value string `json:"value"`
+}
+
+func (f Footer) String() string {
+	if strings.HasPrefix(f.Value, "#") {
+		return f.Token + " " + f.Value
+	}
+	return f.Token + ": " + f.Value
+}
+
+// Commit is a parsed commit message. Type, Scope and Breaking are only set
+// when the header follows the Conventional Commits format.
+type Commit struct {
+	Header       string
+	Emoji        string
+	EmojiPos     string
+	Type         string
+	Scope        string
+	Breaking     bool
+	Subject      string
+	Body         string
+	Footers      []Footer
+	Conventional bool
+}
+
+// shortcodePattern matches a :shortcode: with at least one letter, so times
+// like 12:30:00 are not taken for one. Callers bound it by whitespace.
+const (
+	shortcodePattern    = `:[a-z0-9_+-]*[a-z][a-z0-9_+-]*:`
+	unicodeEmojiPattern = `[\x{1F000}-\x{1FAFF}\x{2190}-\x{2BFF}][\x{FE0F}\x{200D}\x{1F000}-\x{1FAFF}\x{2600}-\x{27BF}]*`
+)
+
+var (
+	headerRegex = regexp.MustCompile(`^(\w+)(?:\(([^)]*)\))?(!)?: (.+)$`)
+	footerRegex = regexp.MustCompile(`^(BREAKING CHANGE|BREAKING-CHANGE|[\w-]+)(: | #)(.*)$`)
+	blankRegex  = regexp.MustCompile(`\n\s*\n`)
+	anyEmoji    = regexp.MustCompile(`(?:^|\s)` + shortcodePattern + `(?:\s|$)|[\x{1F000}-\x{1FAFF}\x{2600}-\x{27BF}]`)
+	emojiRegex  = regexp.MustCompile(`^(?:(` + shortcodePattern + `)(?:\s+|$)|(` + unicodeEmojiPattern + `)\s*)`)
+	endEmoji    = regexp.MustCompile(`\s+(` + shortcodePattern + `|` + unicodeEmojiPattern + `)$`)
+)
+
+// Parse splits message into header, body and footers. Lines starting with
+// "#" are kept; run messages from an editor or hook through StripComments
+// first.
+func Parse(message string) Commit {
+	message = strings.TrimSpace(message)
+	header, rest, _ := strings.Cut(message, "\n")
+
+	commit := Commit{Header: strings.TrimSpace(header)}
+	parseHeader(&commit)
+
+	paragraphs := splitParagraphs(rest)
+	if n := len(paragraphs); n > 0 {
+		if footers, ok := parseFooters(paragraphs[n-1]); ok {
+			commit.Footers = footers
+			paragraphs = paragraphs[:n-1]
+		}
+	}
+	commit.Body = strings.Join(paragraphs, "\n\n")
+
+	for _, footer := range commit.Footers {
+		if footer.Token == "BREAKING CHANGE" || footer.Token == "BREAKING-CHANGE" {
+			commit.Breaking = true
+		}
+	}
+
+	return commit
+}
+
+func parseHeader(commit *Commit) {
+	header := commit.Header
+
+	if emoji := emojiRegex.FindStringSubmatch(header); emoji != nil {
+		commit.Emoji = emoji[1] + emoji[2]
+		commit.EmojiPos = EmojiBeforeType
+		header = header[len(emoji[0]):]
+	}
+
+	defer func() {
+		if commit.Emoji != "" {
+			return
+		}
+		if emoji := endEmoji.FindStringSubmatch(commit.Subject); emoji != nil {
+			commit.Emoji = emoji[1]
+			commit.EmojiPos = EmojiEnd
+			commit.Subject = commit.Subject[:len(commit.Subject)-len(emoji[0])]
+		}
+	}()
+
+	matches := headerRegex.FindStringSubmatch(header)
+	if matches == nil {
+		commit.Subject = header
+		return
+	}
+
+	commit.Conventional = true
+	commit.Type = matches[1]
+	commit.Scope = matches[2]
+	commit.Breaking = matches[3] == "!"
+	commit.Subject = matches[4]
+
+	if commit.Emoji == "" {
+		if emoji := emojiRegex.FindStringSubmatch(commit.Subject); emoji != nil {
+			commit.Emoji = emoji[1] + emoji[2]
+			commit.EmojiPos = EmojiAfterType
+			commit.Subject = commit.Subject[len(emoji[0]):]
+		}
+	}
+}
+
+func splitParagraphs(text string) []string {
+	var paragraphs []string
+	for _, p := range blankRegex.Split(strings.Trim(text, "\n"), -1) {
+		if strings.TrimSpace(p) != "" {
+			paragraphs = append(paragraphs, strings.Trim(p, "\n"))
+		}
+	}
+	return paragraphs
+}
+
+func parseFooters(paragraph string) ([]Footer, bool) {
+	var footers []Footer
+	for _, line := range strings.Split(paragraph, "\n") {
+		if matches := footerRegex.FindStringSubmatch(line); matches != nil {
+			value := matches[3]
+			if matches[2] == " #" {
+				value = "#" + value
+			}
+			footers = append(footers, Footer{Token: matches[1], Value: value})
+			continue
+		}
+		if len(footers) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			footers[len(footers)-1].Value += "\n" + line
+			continue
+		}
+		return nil, false
+	}
+	return footers, len(footers) > 0
+}
+
+// StripComments removes git comment lines and the scissors section that
+// git adds to commit message files.
+func StripComments(message string) string {
+	var lines []string
+	for _, line := range strings.Split(message, "\n") {
+		if strings.HasPrefix(line, "# ------------------------ >8 ------------------------") {
+			break
+		}
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// FormatHeader builds the header from the parsed parts, placing the emoji
+// according to EmojiPos. Non-conventional commits put an emoji that is not
+// at the end in front of the subject.
+func (c Commit) FormatHeader() string {
+	subject := c.Subject
+	prefix := ""
+
+	if c.Emoji != "" {
+		switch {
+		case c.EmojiPos == EmojiEnd:
+			subject += " " + c.Emoji
+		case c.EmojiPos == EmojiAfterType && c.Conventional:
+			subject = c.Emoji + " " + subject
+		default:
+			prefix = c.Emoji + " "
+		}
+	}
+
+	if !c.Conventional {
+		return prefix + subject
+	}
+
+	header := c.Type
+	if c.Scope != "" {
+		header += "(" + c.Scope + ")"
+	}
+	if c.Breaking {
+		header += "!"
+	}
+	return fmt.Sprintf("%s%s: %s", prefix, header, subject)
+}
+
+// String renders the commit back into a message.
+func (c Commit) String() string {
+	parts := []string{c.FormatHeader()}
+	if c.Body != "" {
+		parts = append(parts, c.Body)
+	}
+	if len(c.Footers) > 0 {
+		footers := make([]string, len(c.Footers))
+		for i, f := range c.Footers {
+			footers[i] = f.String()
+		}
+		parts = append(parts, strings.Join(footers, "\n"))
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// Footer returns the value of the first footer with token.
+func (c Commit) Footer(token string) (string, bool) {
+	for _, footer := range c.Footers {
+		if strings.EqualFold(footer.Token, token) {
+			return footer.Value, true
+		}
+	}
+	return "", false
+}
+
+// IsAutomatic reports whether the header belongs to a message git or a tool
+// generates, such as merges, reverts and fixups, which are exempt from linting.
+func IsAutomatic(header string) bool {
+	for _, prefix := range []string{"Merge ", "Revert \"", "fixup! ", "squash! ", "amend! "} {
+		if strings.HasPrefix(header, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasEmoji reports whether text contains a unicode emoji or a :shortcode:
+// standing on its own.
+func HasEmoji(text string) bool {
+	return anyEmoji.MatchString(text)
+}
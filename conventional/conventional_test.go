@@ -0,0 +1,73 @@
+package conventional
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	message := `✨ feat(api)!: add refund endpoint
+
+Adds POST /refunds.
+
+Refs: PAY-1234
+Signed-off-by: Ada <ada@example.com>
+# Please enter the commit message for your changes.`
+
+	c := Parse(StripComments(message))
+
+	if !c.Conventional || c.Type != "feat" || c.Scope != "api" || !c.Breaking {
+		t.Fatalf("unexpected header parse: %+v", c)
+	}
+	if c.Emoji != "✨" || c.Subject != "add refund endpoint" {
+		t.Errorf("Emoji, Subject = %q, %q", c.Emoji, c.Subject)
+	}
+	if c.Body != "Adds POST /refunds." {
+		t.Errorf("Body = %q", c.Body)
+	}
+	if len(c.Footers) != 2 {
+		t.Fatalf("Footers = %+v", c.Footers)
+	}
+	if v, ok := c.Footer("refs"); !ok || v != "PAY-1234" {
+		t.Errorf("Footer(refs) = %q, %t", v, ok)
+	}
+	if got := c.String(); got != "✨ feat(api)!: add refund endpoint\n\nAdds POST /refunds.\n\nRefs: PAY-1234\nSigned-off-by: Ada <ada@example.com>" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestParseNonConventional(t *testing.T) {
+	c := Parse("Update the readme\n\nBREAKING CHANGE: drops install script")
+
+	if c.Conventional {
+		t.Error("expected non-conventional header")
+	}
+	if c.Subject != "Update the readme" {
+		t.Errorf("Subject = %q", c.Subject)
+	}
+	if !c.Breaking {
+		t.Error("expected BREAKING CHANGE footer to mark the commit as breaking")
+	}
+}
+
+func TestHasEmoji(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"fix: retry at 12:30:00", false},
+		{"fix: map key:value:pair", false},
+		{"fix: retry :bug: later", true},
+		{":sparkles: feat: add refunds", true},
+		{"feat: add refunds :sparkles:", true},
+		{"✨ feat: add refunds", true},
+		{"fix: handle empty diff", false},
+	}
+
+	for _, tt := range tests {
+		if got := HasEmoji(tt.text); got != tt.want {
+			t.Errorf("HasEmoji(%q) = %t, want %t", tt.text, got, tt.want)
+		}
+	}
+
+	if c := Parse("fix: retry at 12:30:00"); c.Emoji != "" || c.Subject != "retry at 12:30:00" {
+		t.Errorf("Parse() Emoji, Subject = %q, %q", c.Emoji, c.Subject)
+	}
+}
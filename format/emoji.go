@@ -0,0 +1,142 @@
+package format
+
+import (
+	"strings"
+
+	"github.com/dinoDanic/diny/conventional"
+)
+
+// EmojiOptions control how ApplyEmoji decorates the header.
+type EmojiOptions struct {
+	Enabled   bool
+	Map       map[string]string
+	Style     string // "unicode" or "shortcode"
+	Placement string // conventional.EmojiBeforeType, EmojiAfterType or EmojiEnd
+}
+
+// Gitmoji is an emoji in both of its renderings.
+type Gitmoji struct {
+	Unicode   string
+	Shortcode string
+}
+
+// DefaultEmoji maps commit types to their gitmoji.
+var DefaultEmoji = map[string]Gitmoji{
+	"feat":     {"✨", ":sparkles:"},
+	"fix":      {"🐛", ":bug:"},
+	"docs":     {"📝", ":memo:"},
+	"style":    {"🎨", ":art:"},
+	"refactor": {"♻️", ":recycle:"},
+	"perf":     {"⚡️", ":zap:"},
+	"test":     {"✅", ":white_check_mark:"},
+	"build":    {"📦️", ":package:"},
+	"ci":       {"👷", ":construction_worker:"},
+	"chore":    {"🔧", ":wrench:"},
+	"revert":   {"⏪️", ":rewind:"},
+	"security": {"🔒️", ":lock:"},
+	"deps":     {"⬆️", ":arrow_up:"},
+	"release":  {"🔖", ":bookmark:"},
+	"init":     {"🎉", ":tada:"},
+	"remove":   {"🔥", ":fire:"},
+	"hotfix":   {"🚑️", ":ambulance:"},
+	"wip":      {"🚧", ":construction:"},
+}
+
+// verbTypes infers a commit type from the first word of a non-conventional
+// subject so plain messages get the same emoji as their conventional twins.
+var verbTypes = map[string]string{
+	"add": "feat", "implement": "feat", "introduce": "feat", "support": "feat", "allow": "feat", "enable": "feat",
+	"fix": "fix", "resolve": "fix", "correct": "fix", "prevent": "fix", "handle": "fix",
+	"document": "docs",
+	"format":   "style",
+	"refactor": "refactor", "restructure": "refactor", "simplify": "refactor", "extract": "refactor", "rename": "refactor", "move": "refactor",
+	"optimize": "perf",
+	"test":     "test",
+	"bump":     "deps", "upgrade": "deps",
+	"revert": "revert",
+	"remove": "remove", "delete": "remove", "drop": "remove",
+	"release": "release",
+	"update":  "chore", "configure": "chore", "clean": "chore",
+}
+
+// EmojiFor returns the emoji for commitType, preferring custom entries over
+// the gitmoji defaults and rendering it in style.
+func EmojiFor(commitType string, custom map[string]string, style string) string {
+	commitType = strings.ToLower(commitType)
+
+	emoji, ok := custom[commitType]
+	if !ok {
+		def, ok := DefaultEmoji[commitType]
+		if !ok {
+			return ""
+		}
+		if style == "shortcode" {
+			return def.Shortcode
+		}
+		return def.Unicode
+	}
+
+	for _, def := range DefaultEmoji {
+		if style == "shortcode" && emoji == def.Unicode {
+			return def.Shortcode
+		}
+		if style != "shortcode" && emoji == def.Shortcode {
+			return def.Unicode
+		}
+	}
+
+	return emoji
+}
+
+// IsGitmoji reports whether emoji is one diny inserts: a gitmoji from the
+// default table, in either rendering, or an entry of custom.
+func IsGitmoji(emoji string, custom map[string]string) bool {
+	if emoji == "" {
+		return false
+	}
+
+	plain := func(s string) string { return strings.TrimSuffix(s, "\uFE0F") }
+	for _, def := range DefaultEmoji {
+		if plain(emoji) == plain(def.Unicode) || emoji == def.Shortcode {
+			return true
+		}
+	}
+	for _, custom := range custom {
+		if plain(emoji) == plain(custom) {
+			return true
+		}
+	}
+	return false
+}
+
+// TypeOf returns the conventional type of commit, inferring it from the first
+// word of the subject when the header is not conventional.
+func TypeOf(commit conventional.Commit) string {
+	if commit.Conventional {
+		return commit.Type
+	}
+
+	first, _, _ := strings.Cut(Imperative(commit.Subject), " ")
+	return verbTypes[strings.ToLower(first)]
+}
+
+// ApplyEmoji replaces whatever emoji the header carries with the configured
+// one for its type, or strips emoji entirely when disabled.
+func ApplyEmoji(message string, opts EmojiOptions) string {
+	header, rest, hasRest := strings.Cut(message, "\n")
+
+	commit := conventional.Parse(header)
+	commit.Emoji = ""
+	commit.EmojiPos = ""
+
+	if opts.Enabled {
+		commit.Emoji = EmojiFor(TypeOf(commit), opts.Map, opts.Style)
+		commit.EmojiPos = opts.Placement
+	}
+
+	header = commit.FormatHeader()
+	if !hasRest {
+		return header
+	}
+	return header + "\n" + rest
+}
@@ -0,0 +1,248 @@
+package format
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/dinoDanic/diny/conventional"
+)
+
+// Options control how Message rewrites a generated commit message.
+type Options struct {
+	SubjectMaxLength int
+	BodyWidth        int
+	Bullet           string
+}
+
+var (
+	fenceRegex     = regexp.MustCompile("(?s)^```[\\w-]*\\s*\\n(.*?)\\n?```$")
+	labelRegex     = regexp.MustCompile(`(?i)^(commit message|message)\s*:\s*\n?`)
+	bulletRegex    = regexp.MustCompile(`^(\s*)([-*•+])\s+(.*)$`)
+	blankRunsRegex = regexp.MustCompile(`\n{3,}`)
+)
+
+// Message deterministically cleans up model output: it strips code fences,
+// quotes and labels, enforces an imperative subject without a trailing
+// period within SubjectMaxLength, normalizes bullets and wraps the body at
+// BodyWidth. Footers are left untouched.
+func Message(message string, opts Options) string {
+	message = StripWrappers(message)
+	if message == "" {
+		return message
+	}
+
+	commit := conventional.Parse(message)
+	commit.Subject = formatSubject(commit, opts.SubjectMaxLength)
+	commit.Body = Body(commit.Body, opts.BodyWidth, opts.Bullet)
+
+	return commit.String()
+}
+
+// FitHeader shortens the subject of message so that its whole header, with
+// emoji, ticket and breaking change marker, fits within maxLength. The body
+// and footers are left untouched.
+func FitHeader(message string, maxLength int) string {
+	header, rest, found := strings.Cut(message, "\n")
+	if maxLength <= 0 || utf8.RuneCountInString(header) <= maxLength {
+		return message
+	}
+
+	commit := conventional.Parse(header)
+	subject := commit.Subject
+	commit.Subject = ""
+	available := maxLength - utf8.RuneCountInString(commit.FormatHeader())
+	commit.Subject = truncateWords(subject, available)
+
+	if !found {
+		return commit.FormatHeader()
+	}
+	return commit.FormatHeader() + "\n" + rest
+}
+
+// StripWrappers removes code fences, surrounding quotes, "Commit message:"
+// labels and stray whitespace around the message.
+func StripWrappers(message string) string {
+	message = strings.ReplaceAll(message, "\r\n", "\n")
+	message = strings.TrimSpace(message)
+
+	for {
+		before := message
+
+		if matches := fenceRegex.FindStringSubmatch(message); matches != nil {
+			message = strings.TrimSpace(matches[1])
+		}
+
+		message = strings.TrimSpace(labelRegex.ReplaceAllString(message, ""))
+
+		// A quote that also appears inside the message is part of it, as in
+		// "`foo` renamed to `bar`"
+		for _, quote := range []string{`"""`, `"`, "'", "`"} {
+			if len(message) > 2*len(quote) && strings.HasPrefix(message, quote) && strings.HasSuffix(message, quote) {
+				inner := message[len(quote) : len(message)-len(quote)]
+				if !strings.Contains(inner, quote[:1]) {
+					message = strings.TrimSpace(inner)
+				}
+				break
+			}
+		}
+
+		if message == before {
+			break
+		}
+	}
+
+	lines := strings.Split(message, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRightFunc(line, unicode.IsSpace)
+	}
+
+	return blankRunsRegex.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")
+}
+
+func formatSubject(commit conventional.Commit, maxLength int) string {
+	subject := strings.TrimSpace(commit.Subject)
+	subject = strings.TrimRight(subject, ".")
+	subject = Imperative(subject)
+
+	if maxLength <= 0 {
+		return subject
+	}
+
+	commit.Subject = ""
+	available := maxLength - utf8.RuneCountInString(commit.FormatHeader())
+	return truncateWords(subject, available)
+}
+
+var danglingWords = map[string]bool{
+	"a": true, "an": true, "and": true, "as": true, "for": true, "from": true, "in": true,
+	"of": true, "on": true, "or": true, "the": true, "to": true, "with": true,
+}
+
+func truncateWords(text string, max int) string {
+	if max <= 0 || utf8.RuneCountInString(text) <= max {
+		return text
+	}
+
+	words := strings.Fields(text)
+	var kept []string
+	length := 0
+	for _, word := range words {
+		add := utf8.RuneCountInString(word)
+		if len(kept) > 0 {
+			add++
+		}
+		if length+add > max {
+			break
+		}
+		kept = append(kept, word)
+		length += add
+	}
+
+	for len(kept) > 1 && danglingWords[strings.ToLower(kept[len(kept)-1])] {
+		kept = kept[:len(kept)-1]
+	}
+
+	if len(kept) == 0 {
+		return string([]rune(text)[:max])
+	}
+
+	return strings.TrimRight(strings.Join(kept, " "), ",;:-")
+}
+
+// Body normalizes the bullets of body to bullet and wraps its text at width.
+// Indented code, URLs and tables are kept as they are.
+func Body(body string, width int, bullet string) string {
+	if body == "" {
+		return body
+	}
+	if bullet == "" {
+		bullet = "-"
+	}
+
+	var paragraphs []string
+	for _, paragraph := range strings.Split(body, "\n\n") {
+		paragraphs = append(paragraphs, formatParagraph(paragraph, width, bullet))
+	}
+
+	return strings.Join(paragraphs, "\n\n")
+}
+
+type block struct {
+	kind   string // "text", "bullet" or "verbatim"
+	indent string
+	text   string
+}
+
+func formatParagraph(paragraph string, width int, bullet string) string {
+	var blocks []block
+
+	for _, line := range strings.Split(paragraph, "\n") {
+		var last *block
+		if len(blocks) > 0 {
+			last = &blocks[len(blocks)-1]
+		}
+
+		switch matches := bulletRegex.FindStringSubmatch(line); {
+		case matches != nil:
+			blocks = append(blocks, block{kind: "bullet", indent: matches[1], text: matches[3]})
+		case last != nil && last.kind == "bullet" && strings.HasPrefix(line, " ") && !strings.Contains(line, "://"):
+			last.text += " " + strings.TrimSpace(line)
+		case isVerbatim(line):
+			blocks = append(blocks, block{kind: "verbatim", text: line})
+		case last != nil && last.kind == "text":
+			last.text += " " + strings.TrimSpace(line)
+		default:
+			blocks = append(blocks, block{kind: "text", text: strings.TrimSpace(line)})
+		}
+	}
+
+	var out []string
+	for _, b := range blocks {
+		switch b.kind {
+		case "bullet":
+			hanging := b.indent + strings.Repeat(" ", utf8.RuneCountInString(bullet)+1)
+			out = append(out, wrap(b.text, width, b.indent+bullet+" ", hanging)...)
+		case "verbatim":
+			out = append(out, b.text)
+		default:
+			out = append(out, wrap(b.text, width, "", "")...)
+		}
+	}
+
+	return strings.Join(out, "\n")
+}
+
+// isVerbatim reports whether line must not be reflowed: indented code, URLs
+// and table-like content.
+func isVerbatim(line string) bool {
+	return strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t") ||
+		strings.Contains(line, "://") || strings.HasPrefix(line, "|")
+}
+
+func wrap(text string, width int, firstPrefix, prefix string) []string {
+	words := strings.Fields(text)
+	if width <= 0 || len(words) == 0 {
+		return []string{firstPrefix + strings.Join(words, " ")}
+	}
+
+	var lines []string
+	current := firstPrefix
+	empty := true
+	for _, word := range words {
+		if !empty && utf8.RuneCountInString(current)+1+utf8.RuneCountInString(word) > width {
+			lines = append(lines, current)
+			current = prefix
+			empty = true
+		}
+		if empty {
+			current += word
+			empty = false
+		} else {
+			current += " " + word
+		}
+	}
+
+	return append(lines, current)
+}
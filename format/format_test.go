@@ -0,0 +1,139 @@
+package format
+
+import "testing"
+
+func TestMessage(t *testing.T) {
+	opts := Options{SubjectMaxLength: 50, BodyWidth: 40, Bullet: "-"}
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			"code fence and period",
+			"```\nfeat(api): Added refund endpoint.\n```",
+			"feat(api): Add refund endpoint",
+		},
+		{
+			"quotes and label",
+			"Commit message: \"fix: handles empty diff\"",
+			"fix: handle empty diff",
+		},
+		{
+			"quotes inside the message",
+			"`foo` renamed to `bar`",
+			"`foo` renamed to `bar`",
+		},
+		{
+			"long subject",
+			"refactor: move the configuration loading into its own package and add tests",
+			"refactor: move the configuration loading into its",
+		},
+		{
+			"body wrap and bullets",
+			"feat: add refunds\n\n* Adds a refund endpoint that validates the amount against the original charge\n• Updates docs",
+			"feat: add refunds\n\n- Adds a refund endpoint that validates\n  the amount against the original charge\n- Updates docs",
+		},
+		{
+			"lines starting with a hash",
+			"docs: describe the release\n\n# Release notes\n\n#123 fixed",
+			"docs: describe the release\n\n# Release notes\n\n#123 fixed",
+		},
+		{
+			"footers untouched",
+			"fix: x\n\nSigned-off-by: A Very Long Name That Would Otherwise Wrap <someone@example.com>",
+			"fix: x\n\nSigned-off-by: A Very Long Name That Would Otherwise Wrap <someone@example.com>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Message(tt.input, opts); got != tt.want {
+				t.Errorf("Message() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFitHeader(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			"fits",
+			"feat!: add refunds\n\nBody",
+			"feat!: add refunds\n\nBody",
+		},
+		{
+			"emoji, scope and breaking marker",
+			"✨ feat(PAY-1234)!: add a refund endpoint that validates amounts\n\nBody",
+			"✨ feat(PAY-1234)!: add a refund endpoint\n\nBody",
+		},
+		{
+			"ticket prefix",
+			"PAY-1234 fix: handle empty diffs in the staged changes view",
+			"PAY-1234 fix: handle empty diffs",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FitHeader(tt.input, 40); got != tt.want {
+				t.Errorf("FitHeader() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImperative(t *testing.T) {
+	tests := map[string]string{
+		"Added retries":        "Add retries",
+		"removes the parser":   "remove the parser",
+		"Formatted output":     "Format output",
+		"dropping support":     "drop support",
+		"tests for the thing":  "tests for the thing",
+		"Changes to the guide": "Changes to the guide",
+		"fixes for flaky runs": "fixes for flaky runs",
+		"Applied patch":        "Apply patch",
+		"Updating deps":        "Update deps",
+		"Made it faster":       "Make it faster",
+		"Readme tweaks":        "Readme tweaks",
+	}
+
+	for input, want := range tests {
+		if got := Imperative(input); got != want {
+			t.Errorf("Imperative(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestApplyEmoji(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		opts  EmojiOptions
+		want  string
+	}{
+		{"before type", "🔧 fix(api): handle nil", EmojiOptions{Enabled: true, Placement: "before-type"}, "🐛 fix(api): handle nil"},
+		{"after type shortcode", "feat: add refunds\n\nbody", EmojiOptions{Enabled: true, Style: "shortcode", Placement: "after-type"}, "feat: :sparkles: add refunds\n\nbody"},
+		{"end", "✨ feat: add refunds", EmojiOptions{Enabled: true, Placement: "end"}, "feat: add refunds ✨"},
+		{"custom map", "fix: x", EmojiOptions{Enabled: true, Map: map[string]string{"fix": ":ambulance:"}}, "🚑️ fix: x"},
+		{"inferred type", "Fixed crash on start", EmojiOptions{Enabled: true}, "🐛 Fixed crash on start"},
+		{"disabled strips", "✨ feat: add refunds", EmojiOptions{}, "feat: add refunds"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ApplyEmoji(tt.input, tt.opts)
+			if got != tt.want {
+				t.Errorf("ApplyEmoji() = %q, want %q", got, tt.want)
+			}
+			if again := ApplyEmoji(got, tt.opts); again != got {
+				t.Errorf("ApplyEmoji() is not idempotent: %q -> %q", got, again)
+			}
+		})
+	}
+}
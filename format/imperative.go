@@ -0,0 +1,97 @@
+package format
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// imperativeVerbs are the verbs whose past tense and gerund forms Imperative
+// rewrites. Irregular forms are listed explicitly.
+var imperativeVerbs = []string{
+	"add", "adjust", "allow", "apply", "bump", "change", "clean", "configure", "convert",
+	"correct", "create", "delete", "deprecate", "disable", "document", "drop", "enable",
+	"ensure", "expose", "extract", "fix", "format", "handle", "hide", "implement",
+	"improve", "increase", "initialize", "integrate", "introduce", "merge", "migrate",
+	"move", "optimize", "prepare", "prevent", "reduce", "refactor", "release", "remove",
+	"rename", "replace", "resolve", "restructure", "return", "revert", "rewrite", "show",
+	"simplify", "skip", "support", "test", "update", "upgrade", "use", "validate",
+}
+
+// thirdPersonVerbs are the verbs whose third person form Imperative
+// rewrites. Verbs like "test" or "change" are left out because their "-s"
+// form usually starts a subject as a plural noun: "tests for the parser".
+var thirdPersonVerbs = []string{
+	"add", "adjust", "allow", "apply", "clean", "configure", "convert", "correct", "create",
+	"delete", "deprecate", "disable", "enable", "ensure", "expose", "extract", "handle",
+	"hide", "implement", "improve", "increase", "initialize", "integrate", "introduce",
+	"migrate", "optimize", "prepare", "prevent", "reduce", "remove", "rename", "replace",
+	"resolve", "restructure", "rewrite", "simplify", "validate",
+}
+
+// doubledVerbs double their final consonant before "-ed" and "-ing".
+var doubledVerbs = []string{"drop", "format", "skip"}
+
+var irregularForms = map[string]string{
+	"made": "make", "makes": "make", "making": "make",
+	"setting": "set",
+	"wrote":   "write", "rewrote": "rewrite", "rewritten": "rewrite",
+	"built": "build", "building": "build",
+	"splitting": "split",
+	"stopped":   "stop", "stopping": "stop",
+}
+
+var imperativeForms = buildImperativeForms()
+
+func buildImperativeForms() map[string]string {
+	forms := map[string]string{}
+	for form, verb := range irregularForms {
+		forms[form] = verb
+	}
+
+	for _, verb := range imperativeVerbs {
+		stem := strings.TrimSuffix(verb, "e")
+		third := verb + "s"
+		switch {
+		case strings.HasSuffix(verb, "y") && !strings.HasSuffix(verb, "ay"):
+			base := strings.TrimSuffix(verb, "y")
+			third = base + "ies"
+			forms[base+"ied"] = verb
+		case strings.HasSuffix(verb, "x") || strings.HasSuffix(verb, "sh") || strings.HasSuffix(verb, "ch"):
+			third = verb + "es"
+			forms[verb+"ed"] = verb
+		case slices.Contains(doubledVerbs, verb):
+			stem = verb + verb[len(verb)-1:]
+			forms[stem+"ed"] = verb
+		default:
+			forms[stem+"ed"] = verb
+		}
+		forms[stem+"ing"] = verb
+
+		if slices.Contains(thirdPersonVerbs, verb) {
+			forms[third] = verb
+		}
+	}
+
+	return forms
+}
+
+// Imperative rewrites the first word of subject to its imperative form,
+// e.g. "Added retries" becomes "Add retries". Unknown words are unchanged.
+func Imperative(subject string) string {
+	first, rest, _ := strings.Cut(subject, " ")
+
+	verb, ok := imperativeForms[strings.ToLower(first)]
+	if !ok {
+		return subject
+	}
+
+	if r := []rune(first); len(r) > 0 && unicode.IsUpper(r[0]) {
+		verb = strings.ToUpper(verb[:1]) + verb[1:]
+	}
+
+	if rest == "" {
+		return verb
+	}
+	return verb + " " + rest
+}
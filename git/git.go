@@ -75,3 +75,332 @@ func GetCommitsByDateRange(startDate, endDate string) ([]string, error) {
 
 	return filteredCommits, nil
 }
+
+// GetCurrentBranch returns the name of the checked out branch, or "HEAD" when detached
+func GetCurrentBranch() (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current branch: %w", err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetUserIdentity returns the configured git user as "Name <email>"
+func GetUserIdentity() (string, error) {
+	name, err := exec.Command("git", "config", "user.name").Output()
+	if err != nil {
+		return "", fmt.Errorf("git user.name is not set: %w", err)
+	}
+
+	email, err := exec.Command("git", "config", "user.email").Output()
+	if err != nil {
+		return "", fmt.Errorf("git user.email is not set: %w", err)
+	}
+
+	return fmt.Sprintf("%s <%s>", strings.TrimSpace(string(name)), strings.TrimSpace(string(email))), nil
+}
+
+// InterpretTrailers appends trailers to message with git interpret-trailers,
+// keeping trailers that are already present in the message
+func InterpretTrailers(message string, trailers []string) (string, error) {
+	args := []string{"interpret-trailers", "--if-exists", "addIfDifferent"}
+	for _, trailer := range trailers {
+		args = append(args, "--trailer", trailer)
+	}
+
+	cmd := exec.Command("git", args...)
+	cmd.Stdin = strings.NewReader(message)
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to interpret trailers: %w", err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetRecentCommitMessages returns the full messages of the last n non-merge commits
+func GetRecentCommitMessages(n int) ([]string, error) {
+	return GetRecentCommitMessagesMatching(n, "", nil)
+}
+
+// GetRecentCommitMessagesMatching returns the full messages of the last n
+// non-merge commits, limited to those by author and touching paths when set
+func GetRecentCommitMessagesMatching(n int, author string, paths []string) ([]string, error) {
+	args := []string{"log",
+		fmt.Sprintf("-n%d", n),
+		"--no-merges",
+		"--pretty=format:%B%x00"}
+
+	if author != "" {
+		args = append(args, "--author="+author)
+	}
+
+	if len(paths) > 0 {
+		args = append(append(args, "--"), paths...)
+	}
+
+	output, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get git log: %w", err)
+	}
+
+	var messages []string
+	for _, message := range strings.Split(string(output), "\x00") {
+		if message = strings.TrimSpace(message); message != "" {
+			messages = append(messages, message)
+		}
+	}
+
+	return messages, nil
+}
+
+// GetUserEmail returns the configured user.email
+func GetUserEmail() (string, error) {
+	output, err := exec.Command("git", "config", "user.email").Output()
+	if err != nil {
+		return "", fmt.Errorf("git user.email is not set: %w", err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetGitDir returns the absolute path of the .git directory
+func GetGitDir() (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--absolute-git-dir")
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("not in a git repository: %w", err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetEditor returns the editor git would use: $GIT_EDITOR, core.editor,
+// $VISUAL, $EDITOR and finally vi
+func GetEditor() string {
+	if editor := os.Getenv("GIT_EDITOR"); editor != "" {
+		return editor
+	}
+
+	if output, err := exec.Command("git", "config", "core.editor").Output(); err == nil {
+		if editor := strings.TrimSpace(string(output)); editor != "" {
+			return editor
+		}
+	}
+
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if editor := os.Getenv(env); editor != "" {
+			return editor
+		}
+	}
+
+	return "vi"
+}
+
+// EmptyTree is the hash of the empty tree, used to diff root commits
+const EmptyTree = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+
+// HasParent reports whether rev has a parent commit
+func HasParent(rev string) bool {
+	return exec.Command("git", "rev-parse", "--verify", "--quiet", rev+"^").Run() == nil
+}
+
+// GetCommitMessage returns the full message of rev
+func GetCommitMessage(rev string) (string, error) {
+	output, err := exec.Command("git", "log", "-1", "--format=%B", rev).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to read commit message: %w", err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// IsPushed reports whether rev is already contained in the upstream of the
+// current branch. Branches without an upstream are never pushed.
+func IsPushed(rev string) bool {
+	if exec.Command("git", "rev-parse", "--verify", "--quiet", "@{upstream}").Run() != nil {
+		return false
+	}
+
+	return exec.Command("git", "merge-base", "--is-ancestor", rev, "@{upstream}").Run() == nil
+}
+
+// GetHeadHash returns the commit hash of HEAD
+func GetHeadHash() (string, error) {
+	output, err := exec.Command("git", "rev-parse", "HEAD").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve HEAD: %w", err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetStagedPatch returns the complete staged diff, including binary files,
+// in a form git apply accepts. The a/ and b/ prefixes are explicit so
+// diff.noprefix and diff.mnemonicPrefix do not change the paths.
+func GetStagedPatch() (string, error) {
+	output, err := exec.Command("git", "diff", "--cached", "--no-color", "--no-ext-diff", "--binary", "--src-prefix=a/", "--dst-prefix=b/").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get staged diff: %w", err)
+	}
+
+	return string(output), nil
+}
+
+// GetUnstagedPatch returns the diff of the working tree against the index,
+// including binary files, in a form git apply accepts
+func GetUnstagedPatch() (string, error) {
+	output, err := exec.Command("git", "diff", "--no-color", "--no-ext-diff", "--binary", "--src-prefix=a/", "--dst-prefix=b/").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get unstaged diff: %w", err)
+	}
+
+	return string(output), nil
+}
+
+// GetUntrackedFiles returns the files git does not track and does not ignore
+func GetUntrackedFiles() ([]string, error) {
+	output, err := exec.Command("git", "ls-files", "--others", "--exclude-standard", "-z").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list untracked files: %w", err)
+	}
+
+	var files []string
+	for _, file := range strings.Split(string(output), "\x00") {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+
+	return files, nil
+}
+
+// AddPaths stages paths as they are in the working tree
+func AddPaths(paths []string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+
+	args := append([]string{"add", "--"}, paths...)
+	if output, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("git add failed: %s", strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
+
+// WriteTree writes the index as a tree object and returns its hash
+func WriteTree() (string, error) {
+	output, err := exec.Command("git", "write-tree").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to write index tree: %w", err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// ReadTree replaces the index with tree, leaving the working tree untouched
+func ReadTree(tree string) error {
+	if output, err := exec.Command("git", "read-tree", tree).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to read tree %s: %s", tree, strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
+
+// ApplyCached applies patch to the index only, or reverts it when reverse is set
+func ApplyCached(patch string, reverse bool) error {
+	args := []string{"apply", "--cached", "--whitespace=nowarn"}
+	if reverse {
+		args = append(args, "--reverse")
+	}
+
+	cmd := exec.Command("git", append(args, "-")...)
+	cmd.Stdin = strings.NewReader(patch)
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git apply failed: %s", strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
+
+// ApplyCachedThreeWay applies patch to the index, falling back to a 3-way
+// merge with the blobs the patch was made from when its context no longer
+// matches. Conflicts make it fail and are left in the index.
+func ApplyCachedThreeWay(patch string) error {
+	cmd := exec.Command("git", "apply", "--cached", "--3way", "--whitespace=nowarn", "-")
+	cmd.Stdin = strings.NewReader(patch)
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git apply failed: %s", strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
+
+// ResetSoft moves the current branch to rev, keeping the index and working tree
+func ResetSoft(rev string) error {
+	if output, err := exec.Command("git", "reset", "--soft", rev).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to reset to %s: %s", rev, strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
+
+// RemoteBranchesContaining lists the remote-tracking branches that contain rev
+func RemoteBranchesContaining(rev string) ([]string, error) {
+	output, err := exec.Command("git", "branch", "--remotes", "--contains", rev, "--format=%(refname:short)").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list remote branches: %w", err)
+	}
+
+	return strings.Fields(string(output)), nil
+}
+
+// IsCommit reports whether rev names an existing commit
+func IsCommit(rev string) bool {
+	return exec.Command("git", "rev-parse", "--verify", "--quiet", rev+"^{commit}").Run() == nil
+}
+
+// ShowFile returns the content of path at rev, or in the index when rev is
+// empty. It returns nil when the path does not exist there.
+func ShowFile(rev, path string) []byte {
+	output, err := exec.Command("git", "show", rev+":"+path).Output()
+	if err != nil {
+		return nil
+	}
+
+	return output
+}
+
+// ListTree returns the paths of the files directly inside dir at rev,
+// relative to the repository root.
+func ListTree(rev, dir string) ([]string, error) {
+	args := []string{"ls-tree", "--full-tree", "--name-only", rev}
+	if dir != "." && dir != "" {
+		args = append(args, "--", dir+"/")
+	}
+
+	output, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s at %s: %w", dir, rev, err)
+	}
+
+	return strings.Fields(string(output)), nil
+}
+
+// MergeBase returns the best common ancestor of a and b
+func MergeBase(a, b string) (string, error) {
+	output, err := exec.Command("git", "merge-base", a, b).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to find the merge base of %s and %s: %w", a, b, err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
@@ -23,20 +23,62 @@ type commitResp struct {
 	Data  *commitData `json:"data,omitempty"`
 }
 
-func CreateCommitMessageWithGroq(gitDiff string, userConfig *config.UserConfig) (string, error) {
+// CommitRequest is what diny sends to generate a commit message.
+type CommitRequest struct {
+	GitDiff string
+	// StyleExamples are recent commit messages of the repository whose
+	// conventions the generated message should follow.
+	StyleExamples []string
+	// Guidance is what diny learned from the user's edits to earlier messages.
+	Guidance []string
+	// Refinement is set when the message answers earlier ones.
+	Refinement *Refinement
+}
+
+// Refinement asks for a new message in response to earlier ones. It is sent
+// apart from the diff so instructions can never be confused with diff lines.
+type Refinement struct {
+	// RejectedMessages are earlier candidates the user did not want.
+	RejectedMessages []string `json:"rejectedMessages,omitempty"`
+	// CurrentMessage is the message the feedback or violations refer to.
+	CurrentMessage string `json:"currentMessage,omitempty"`
+	// Feedback is what the user asked to change.
+	Feedback string `json:"feedback,omitempty"`
+	// CombineMessages are candidates whose best ideas should be merged.
+	CombineMessages []string `json:"combineMessages,omitempty"`
+	// Violations are the commitlint problems of CurrentMessage.
+	Violations []string `json:"violations,omitempty"`
+	// Variant and Variants ask for one of several differing candidates.
+	Variant  int `json:"variant,omitempty"`
+	Variants int `json:"variants,omitempty"`
+}
+
+func CreateCommitMessageWithGroq(request CommitRequest, userConfig *config.UserConfig) (string, error) {
 	gitInfo, err := config.GetGitInfo()
 	if err != nil {
 		return "", fmt.Errorf("failed to get git info: %w", err)
 	}
 
 	payload := map[string]interface{}{
-		"gitDiff":   gitDiff,
+		"gitDiff":   request.GitDiff,
 		"version":   version.Get(),
 		"repoName":  gitInfo.RepoName,
 		"repoOwner": gitInfo.RepoOwner,
 		"repoURL":   gitInfo.RepoURL,
 	}
 
+	if len(request.StyleExamples) > 0 {
+		payload["styleExamples"] = request.StyleExamples
+	}
+
+	if len(request.Guidance) > 0 {
+		payload["learnedGuidance"] = request.Guidance
+	}
+
+	if request.Refinement != nil {
+		payload["refinement"] = request.Refinement
+	}
+
 	if userConfig != nil {
 		payload["userConfig"] = *userConfig
 	} else {
@@ -0,0 +1,211 @@
+package history
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dinoDanic/diny/config"
+	"github.com/dinoDanic/diny/git"
+)
+
+// Outcome is how a generation session ended.
+type Outcome string
+
+const (
+	Committed Outcome = "committed"
+	Printed   Outcome = "printed"
+	Abandoned Outcome = "abandoned"
+	// Undone records a commit reverted by diny undo. Its message is kept so
+	// it can be reused.
+	Undone Outcome = "undone"
+)
+
+// Session is one run of diny commit or diny message: every message that was
+// generated, the feedback given and the message that was finally used.
+type Session struct {
+	Time          time.Time          `json:"time"`
+	Command       string             `json:"command"`
+	DiffHash      string             `json:"diffHash"`
+	Config        *config.UserConfig `json:"config,omitempty"`
+	Candidates    []string           `json:"candidates"`
+	Feedback      []string           `json:"feedback,omitempty"`
+	Regenerations int                `json:"regenerations"`
+	Message       string             `json:"message,omitempty"`
+	Edited        bool               `json:"edited"`
+	Commit        string             `json:"commit,omitempty"`
+	Base          string             `json:"base,omitempty"`
+	Outcome       Outcome            `json:"outcome"`
+}
+
+// NewSession starts recording a session for diff.
+func NewSession(command, diff string, userConfig *config.UserConfig) *Session {
+	return &Session{
+		Time:     time.Now(),
+		Command:  command,
+		DiffHash: HashDiff(diff),
+		Config:   userConfig,
+	}
+}
+
+// HashDiff identifies a diff without storing it.
+func HashDiff(diff string) string {
+	sum := sha256.Sum256([]byte(diff))
+	return hex.EncodeToString(sum[:])
+}
+
+// AddCandidates records generated messages. It is a no-op on a nil session.
+func (s *Session) AddCandidates(messages ...string) {
+	if s == nil {
+		return
+	}
+	s.Candidates = append(s.Candidates, messages...)
+}
+
+// AddRegeneration records a request for a different message.
+func (s *Session) AddRegeneration() {
+	if s == nil {
+		return
+	}
+	s.Regenerations++
+}
+
+// AddFeedback records refinement feedback given by the user.
+func (s *Session) AddFeedback(feedback string) {
+	if s == nil || strings.TrimSpace(feedback) == "" {
+		return
+	}
+	s.Feedback = append(s.Feedback, feedback)
+}
+
+// SetBase records the HEAD the commit was made on, or the commit it amended,
+// which is where diny undo returns to.
+func (s *Session) SetBase(base string) {
+	if s == nil {
+		return
+	}
+	s.Base = base
+}
+
+// Finish sets the outcome and the message that was used, and appends the
+// session to the history file.
+func (s *Session) Finish(outcome Outcome, message string) error {
+	if s == nil {
+		return nil
+	}
+
+	s.Outcome = outcome
+	s.Message = message
+	s.Edited = message != "" && !slices.Contains(s.Candidates, message)
+
+	if outcome == Committed {
+		s.Commit, _ = git.GetHeadHash()
+	}
+
+	return Append(*s)
+}
+
+// Subject returns the first line of the message that was used, or of the
+// last candidate when the session was abandoned.
+func (s Session) Subject() string {
+	subject, _, _ := strings.Cut(s.LastMessage(), "\n")
+	return subject
+}
+
+// LastMessage returns the message that was used, or the last candidate.
+func (s Session) LastMessage() string {
+	if s.Message != "" {
+		return s.Message
+	}
+	if len(s.Candidates) > 0 {
+		return s.Candidates[len(s.Candidates)-1]
+	}
+	return ""
+}
+
+// Path returns the location of the history file.
+func Path() (string, error) {
+	gitDir, err := git.GetGitDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(gitDir, "diny-history.jsonl"), nil
+}
+
+// Append adds session to the history file.
+func Append(session Session) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+
+	line, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("marshal session: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open history: %w", err)
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
+
+// Load reads every session, newest first. A missing file is an empty history
+// and lines that cannot be parsed are skipped.
+func Load() ([]Session, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open history: %w", err)
+	}
+	defer file.Close()
+
+	var sessions []Session
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		var session Session
+		if err := json.Unmarshal(scanner.Bytes(), &session); err != nil {
+			continue
+		}
+		sessions = append(sessions, session)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+
+	slices.Reverse(sessions)
+	return sessions, nil
+}
+
+// Matches reports whether a candidate, the final message or the feedback
+// contains query, case-insensitively.
+func (s Session) Matches(query string) bool {
+	query = strings.ToLower(query)
+
+	texts := append(append([]string{s.Message}, s.Candidates...), s.Feedback...)
+	return slices.ContainsFunc(texts, func(text string) bool {
+		return strings.Contains(strings.ToLower(text), query)
+	})
+}
@@ -0,0 +1,39 @@
+package history
+
+import "testing"
+
+func TestMatches(t *testing.T) {
+	session := Session{
+		Candidates: []string{"feat: add rate limiter"},
+		Feedback:   []string{"mention Redis"},
+		Message:    "feat(api): add Redis rate limiter",
+	}
+
+	for _, query := range []string{"RATE LIMIT", "redis", "api"} {
+		if !session.Matches(query) {
+			t.Errorf("Matches(%q) = false, want true", query)
+		}
+	}
+
+	if session.Matches("database") {
+		t.Error(`Matches("database") = true, want false`)
+	}
+}
+
+func TestComputeStats(t *testing.T) {
+	sessions := []Session{
+		{Outcome: Committed, Candidates: []string{"a"}, Message: "a"},
+		{Outcome: Committed, Candidates: []string{"a", "b"}, Message: "b", Regenerations: 1},
+		{Outcome: Committed, Candidates: []string{"a"}, Message: "c", Edited: true},
+		{Outcome: Abandoned, Candidates: []string{"a", "b", "c"}, Regenerations: 2, Feedback: []string{"shorter"}},
+		{Outcome: Printed, Candidates: []string{"a"}, Message: "a"},
+		{Outcome: Undone, Candidates: []string{"a"}, Message: "a"},
+	}
+
+	got := ComputeStats(sessions)
+	want := Stats{Sessions: 5, Committed: 3, Printed: 1, Abandoned: 1, Undone: 1, FirstAccepted: 1, Edited: 1, Regenerations: 3, Refinements: 1}
+
+	if got != want {
+		t.Errorf("ComputeStats() = %+v, want %+v", got, want)
+	}
+}
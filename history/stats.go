@@ -0,0 +1,81 @@
+package history
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Stats summarizes how generated messages were received.
+type Stats struct {
+	Sessions      int
+	Committed     int
+	Printed       int
+	Abandoned     int
+	Undone        int
+	FirstAccepted int
+	Edited        int
+	Regenerations int
+	Refinements   int
+}
+
+// ComputeStats aggregates sessions.
+func ComputeStats(sessions []Session) Stats {
+	var stats Stats
+
+	for _, session := range sessions {
+		if session.Outcome == Undone {
+			stats.Undone++
+			continue
+		}
+
+		stats.Sessions++
+		stats.Regenerations += session.Regenerations
+		stats.Refinements += len(session.Feedback)
+
+		switch session.Outcome {
+		case Committed:
+			stats.Committed++
+			if session.Edited {
+				stats.Edited++
+			} else if len(session.Candidates) > 0 && session.Message == session.Candidates[0] {
+				stats.FirstAccepted++
+			}
+		case Printed:
+			stats.Printed++
+		default:
+			stats.Abandoned++
+		}
+	}
+
+	return stats
+}
+
+func (s Stats) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Sessions:            %d\n", s.Sessions)
+	fmt.Fprintf(&b, "Committed:           %d (%s)\n", s.Committed, percent(s.Committed, s.Sessions))
+	fmt.Fprintf(&b, "Printed:             %d\n", s.Printed)
+	fmt.Fprintf(&b, "Abandoned:           %d\n", s.Abandoned)
+	fmt.Fprintf(&b, "Undone:              %d\n", s.Undone)
+	fmt.Fprintf(&b, "Accepted first try:  %d (%s of commits)\n", s.FirstAccepted, percent(s.FirstAccepted, s.Committed))
+	fmt.Fprintf(&b, "Edited before use:   %d (%s of commits)\n", s.Edited, percent(s.Edited, s.Committed))
+	fmt.Fprintf(&b, "Regenerations:       %d (%.1f per session)\n", s.Regenerations, average(s.Regenerations, s.Sessions))
+	fmt.Fprintf(&b, "Refinements:         %d (%.1f per session)", s.Refinements, average(s.Refinements, s.Sessions))
+
+	return b.String()
+}
+
+func percent(n, total int) string {
+	if total == 0 {
+		return "0%"
+	}
+	return fmt.Sprintf("%.0f%%", float64(n)*100/float64(total))
+}
+
+func average(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) / float64(total)
+}
@@ -0,0 +1,115 @@
+package learn
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/dinoDanic/diny/conventional"
+)
+
+// GuidanceCount is how many learned instructions are sent with a request.
+const GuidanceCount = 5
+
+// recentCorrections limits guidance to the latest corrections so old habits
+// fade out.
+const recentCorrections = 50
+
+// Guidance turns corrections into at most n short instructions, the most
+// frequent first and the most recent among equally frequent ones.
+func Guidance(corrections []Correction, n int) []string {
+	if len(corrections) > recentCorrections {
+		corrections = corrections[:recentCorrections]
+	}
+
+	counts := make(map[string]int)
+	var order []string
+
+	for _, correction := range corrections {
+		for _, lesson := range Lessons(correction) {
+			if counts[lesson] == 0 {
+				order = append(order, lesson)
+			}
+			counts[lesson]++
+		}
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return counts[order[i]] > counts[order[j]]
+	})
+
+	if len(order) > n {
+		order = order[:n]
+	}
+
+	return order
+}
+
+// Lessons describes what the user changed between the generated and the
+// final message of correction.
+func Lessons(correction Correction) []string {
+	generated := conventional.Parse(correction.Generated)
+	final := conventional.Parse(correction.Final)
+
+	var lessons []string
+
+	switch {
+	case final.Conventional && !generated.Conventional:
+		lessons = append(lessons, "Use the Conventional Commits format")
+	case !final.Conventional && generated.Conventional:
+		lessons = append(lessons, "Do not use the Conventional Commits format")
+	case final.Conventional && generated.Type != final.Type:
+		lessons = append(lessons, fmt.Sprintf("Use type %q where %q was suggested", final.Type, generated.Type))
+	}
+
+	if final.Conventional && generated.Scope != final.Scope {
+		if final.Scope == "" {
+			lessons = append(lessons, "Do not add a scope")
+		} else {
+			lessons = append(lessons, fmt.Sprintf("Use scope %q", final.Scope))
+		}
+	}
+
+	generatedWord, _, _ := strings.Cut(generated.Subject, " ")
+	finalWord, _, _ := strings.Cut(final.Subject, " ")
+	if generatedWord != "" && !strings.EqualFold(generatedWord, finalWord) {
+		lessons = append(lessons, fmt.Sprintf("Do not start the subject with %q", generatedWord))
+	}
+
+	if generatedFirst, finalFirst := firstRune(generated.Subject), firstRune(final.Subject); unicode.IsLetter(generatedFirst) && unicode.IsLetter(finalFirst) {
+		switch {
+		case unicode.IsLower(generatedFirst) && unicode.IsUpper(finalFirst):
+			lessons = append(lessons, "Start the subject with a capital letter")
+		case unicode.IsUpper(generatedFirst) && unicode.IsLower(finalFirst):
+			lessons = append(lessons, "Start the subject with a lowercase letter")
+		}
+	}
+
+	generatedLength, finalLength := len(generated.Subject), len(final.Subject)
+	switch {
+	case finalLength*4 < generatedLength*3:
+		lessons = append(lessons, "Keep the subject shorter")
+	case finalLength*3 > generatedLength*4:
+		lessons = append(lessons, "Write a more descriptive subject")
+	}
+
+	switch {
+	case generated.Body != "" && final.Body == "":
+		lessons = append(lessons, "Leave out the body")
+	case generated.Body == "" && final.Body != "":
+		lessons = append(lessons, "Add a body that explains the change")
+	}
+
+	for _, feedback := range correction.Feedback {
+		lessons = append(lessons, fmt.Sprintf("Earlier feedback: %q", strings.TrimSpace(feedback)))
+	}
+
+	return lessons
+}
+
+func firstRune(s string) rune {
+	r, _ := utf8.DecodeRuneInString(s)
+	return r
+}
@@ -0,0 +1,42 @@
+package learn
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLessons(t *testing.T) {
+	correction := Correction{
+		Generated: "feat: Update the button colors\n\n- tweak palette",
+		Final:     "feat(ui): change button colors",
+		Feedback:  []string{"mention the palette"},
+	}
+
+	got := Lessons(correction)
+	want := []string{
+		`Use scope "ui"`,
+		`Do not start the subject with "Update"`,
+		"Start the subject with a lowercase letter",
+		"Leave out the body",
+		`Earlier feedback: "mention the palette"`,
+	}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("Lessons() =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestGuidance(t *testing.T) {
+	corrections := []Correction{
+		{Generated: "feat: add login", Final: "feat(auth): add login"},
+		{Generated: "fix: Update parser", Final: "fix: handle empty input in parser"},
+		{Generated: "feat: add logout", Final: "feat(auth): add logout"},
+	}
+
+	got := Guidance(corrections, 2)
+	want := []string{`Use scope "auth"`, `Do not start the subject with "Update"`}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("Guidance() = %q, want %q", got, want)
+	}
+}
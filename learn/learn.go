@@ -0,0 +1,163 @@
+package learn
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dinoDanic/diny/git"
+	"github.com/dinoDanic/diny/history"
+)
+
+// Correction pairs a generated message with the message that was committed
+// after the user edited or refined it.
+type Correction struct {
+	Time      time.Time `json:"time"`
+	Commit    string    `json:"commit,omitempty"`
+	Generated string    `json:"generated"`
+	Final     string    `json:"final"`
+	Feedback  []string  `json:"feedback,omitempty"`
+}
+
+// FromSession returns the correction made in a committed session: the last
+// candidate against an edited message, or the first candidate against a
+// message refined with feedback.
+func FromSession(session history.Session) (Correction, bool) {
+	if session.Outcome != history.Committed || len(session.Candidates) == 0 {
+		return Correction{}, false
+	}
+
+	var generated string
+	switch {
+	case session.Edited:
+		generated = session.Candidates[len(session.Candidates)-1]
+	case len(session.Feedback) > 0:
+		generated = session.Candidates[0]
+	default:
+		return Correction{}, false
+	}
+
+	if strings.TrimSpace(generated) == strings.TrimSpace(session.Message) {
+		return Correction{}, false
+	}
+
+	return Correction{
+		Time:      time.Now(),
+		Commit:    session.Commit,
+		Generated: generated,
+		Final:     session.Message,
+		Feedback:  session.Feedback,
+	}, true
+}
+
+// RecordSession records the correction made in session, if any.
+func RecordSession(session *history.Session) error {
+	if session == nil {
+		return nil
+	}
+
+	correction, ok := FromSession(*session)
+	if !ok {
+		return nil
+	}
+
+	return Record(correction)
+}
+
+// Path returns the location of the corrections file.
+func Path() (string, error) {
+	gitDir, err := git.GetGitDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(gitDir, "diny-corrections.jsonl"), nil
+}
+
+// Record appends correction, skipping commits that were already recorded.
+func Record(correction Correction) error {
+	if correction.Commit != "" {
+		corrections, err := Load()
+		if err != nil {
+			return err
+		}
+		if slices.ContainsFunc(corrections, func(c Correction) bool { return c.Commit == correction.Commit }) {
+			return nil
+		}
+	}
+
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+
+	line, err := json.Marshal(correction)
+	if err != nil {
+		return fmt.Errorf("marshal correction: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open corrections: %w", err)
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
+
+// Load reads every correction, newest first. A missing file means nothing
+// was learned yet and lines that cannot be parsed are skipped.
+func Load() ([]Correction, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open corrections: %w", err)
+	}
+	defer file.Close()
+
+	var corrections []Correction
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		var correction Correction
+		if err := json.Unmarshal(scanner.Bytes(), &correction); err != nil {
+			continue
+		}
+		corrections = append(corrections, correction)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read corrections: %w", err)
+	}
+
+	slices.Reverse(corrections)
+	return corrections, nil
+}
+
+// Clear forgets everything that was learned.
+func Clear() error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove corrections: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dinoDanic/diny/commitlint"
+	"github.com/dinoDanic/diny/config"
+	"github.com/dinoDanic/diny/conventional"
+	"github.com/dinoDanic/diny/format"
+)
+
+// Check validates message against the diny configuration and, when present,
+// the repository's commitlint rules. Merge, revert and fixup messages are
+// always accepted.
+func Check(message string, userConfig *config.UserConfig, lintConfig *commitlint.Config) []commitlint.Violation {
+	message = strings.TrimSpace(conventional.StripComments(message))
+	commit := conventional.Parse(message)
+
+	var violations []commitlint.Violation
+	add := func(rule string, level commitlint.Level, format string, args ...interface{}) {
+		violations = append(violations, commitlint.Violation{Rule: rule, Level: level, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if commit.Header == "" {
+		add("subject-empty", commitlint.Error, "message must not be empty")
+		return violations
+	}
+
+	if conventional.IsAutomatic(commit.Header) {
+		return nil
+	}
+
+	if max, length := userConfig.SubjectMaxLengthOrDefault(), len([]rune(commit.Header)); length > max {
+		add("subject-max-length", commitlint.Error, "header must not be longer than %d characters, current length is %d", max, length)
+	}
+
+	if lines := strings.Split(message, "\n"); len(lines) > 1 && strings.TrimSpace(lines[1]) != "" {
+		add("body-leading-blank", commitlint.Error, "header must be followed by a blank line")
+	}
+
+	if userConfig != nil {
+		violations = append(violations, checkConfig(commit, userConfig)...)
+	}
+
+	return append(violations, lintConfig.Lint(message)...)
+}
+
+func checkConfig(commit conventional.Commit, userConfig *config.UserConfig) []commitlint.Violation {
+	var violations []commitlint.Violation
+	add := func(rule string, level commitlint.Level, format string, args ...interface{}) {
+		violations = append(violations, commitlint.Violation{Rule: rule, Level: level, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if userConfig.UseConventional {
+		if !commit.Conventional {
+			add("conventional-format", commitlint.Error, "header must follow the conventional format type(scope): subject")
+		} else {
+			types := userConfig.Types
+			if len(types) == 0 {
+				types = conventional.DefaultTypes
+			}
+			if !contains(types, commit.Type) {
+				add("type-enum", commitlint.Error, "type %q must be one of [%s]", commit.Type, strings.Join(types, ", "))
+			}
+		}
+	}
+
+	if commit.Subject == "" {
+		add("subject-empty", commitlint.Error, "subject must not be empty")
+	}
+
+	hasEmoji := conventional.HasEmoji(commit.Header)
+	if userConfig.UseEmoji && !hasEmoji {
+		add("emoji-policy", commitlint.Warning, "header should include an emoji")
+	}
+	if userConfig.UseEmoji && hasEmoji {
+		expected := format.EmojiFor(format.TypeOf(commit), userConfig.EmojiMap, string(userConfig.EmojiStyleOrDefault()))
+		if expected != "" && commit.Emoji != expected {
+			add("emoji-policy", commitlint.Warning, "emoji for %q should be %s", format.TypeOf(commit), expected)
+		}
+	}
+	// Only the gitmoji diny would add count, symbols like ☕ or ✓ are text
+	if !userConfig.UseEmoji && format.IsGitmoji(commit.Emoji, userConfig.EmojiMap) {
+		add("emoji-policy", commitlint.Error, "header must not include emoji")
+	}
+
+	if userConfig.Signoff {
+		if _, ok := commit.Footer("Signed-off-by"); !ok {
+			add("signed-off-by", commitlint.Error, "message must have a Signed-off-by trailer")
+		}
+	}
+
+	for _, trailer := range userConfig.Trailers {
+		token, _, _ := strings.Cut(trailer, ":")
+		if _, ok := commit.Footer(strings.TrimSpace(token)); !ok {
+			add("trailer-exists", commitlint.Error, "message must have a %s trailer", strings.TrimSpace(token))
+		}
+	}
+
+	return violations
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,33 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/dinoDanic/diny/config"
+)
+
+func TestCheckEmojiPolicy(t *testing.T) {
+	userConfig := &config.UserConfig{UseConventional: true}
+
+	tests := []struct {
+		message string
+		want    bool
+	}{
+		{"fix: café ☕ stuff", false},
+		{"feat: x ✓", false},
+		{"fix: retry at 12:30:00", false},
+		{"✨ feat: add refunds", true},
+		{"feat: :sparkles: add refunds", true},
+		{"feat: add refunds ♻️", true},
+	}
+
+	for _, tt := range tests {
+		got := false
+		for _, violation := range Check(tt.message, userConfig, nil) {
+			got = got || violation.Rule == "emoji-policy"
+		}
+		if got != tt.want {
+			t.Errorf("Check(%q) reported emoji-policy = %t, want %t", tt.message, got, tt.want)
+		}
+	}
+}
@@ -10,8 +10,7 @@ import (
 	"strings"
 )
 
-// const server = "http://127.0.0.1:11434"
-const server = "http://167.235.150.40"
+const server = "http://127.0.0.1:11434"
 
 // const model = "qwen2.5:7b-instruct"
 // const model = "qwen2.5-coder:3b"
@@ -83,6 +82,14 @@ func MainStream(prompt string) (string, error) {
 }
 
 func Main(prompt string) (string, error) {
+	fmt.Println("🐢 My tiny server is thinking hard, thanks for your patience!")
+
+	return Generate(server, prompt)
+}
+
+// Generate returns the complete response of the Ollama server at url to
+// prompt without printing anything.
+func Generate(url, prompt string) (string, error) {
 	req := GenerateRequest{
 		Model:  model,
 		Prompt: prompt,
@@ -94,9 +101,7 @@ func Main(prompt string) (string, error) {
 		return "", fmt.Errorf("error marshaling JSON: %v", err)
 	}
 
-	fmt.Println("🐢 My tiny server is thinking hard, thanks for your patience!")
-
-	resp, err := http.Post(server+"/api/generate", "application/json", bytes.NewBuffer(jsonData))
+	resp, err := http.Post(strings.TrimRight(url, "/")+"/api/generate", "application/json", bytes.NewBuffer(jsonData))
 
 	if err != nil {
 		return "", fmt.Errorf("error calling Ollama: %v", err)
@@ -0,0 +1,183 @@
+package patch
+
+import (
+	"strings"
+)
+
+// File is the part of a git diff that changes one file.
+type File struct {
+	Path string
+	// Header holds the lines from "diff --git" up to the first hunk.
+	Header []string
+	Hunks  []Hunk
+	// Whole is set when the file can only be applied as a whole: added,
+	// deleted, renamed, copied, binary and mode-only changes.
+	Whole bool
+	// Lines is the complete text of the file's diff.
+	Lines []string
+}
+
+// Hunk is one "@@" section of a file diff.
+type Hunk struct {
+	Header string
+	Lines  []string
+}
+
+// Unit is the smallest part of a diff that can be staged on its own: a hunk
+// of a file, or the whole file when Hunk is -1.
+type Unit struct {
+	File int
+	Hunk int
+}
+
+// Parse splits a diff produced by git diff into files and hunks.
+func Parse(diff string) []File {
+	var files []File
+	var current *File
+
+	for _, line := range strings.Split(strings.TrimRight(diff, "\n"), "\n") {
+		if strings.HasPrefix(line, "diff --git ") {
+			files = append(files, File{})
+			current = &files[len(files)-1]
+		}
+		if current == nil {
+			continue
+		}
+
+		current.Lines = append(current.Lines, line)
+
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			current.Hunks = append(current.Hunks, Hunk{Header: line})
+		case len(current.Hunks) > 0:
+			hunk := &current.Hunks[len(current.Hunks)-1]
+			hunk.Lines = append(hunk.Lines, line)
+		default:
+			current.Header = append(current.Header, line)
+		}
+	}
+
+	for i := range files {
+		files[i].Path = headerPath(files[i].Header)
+		files[i].Whole = isWhole(files[i])
+	}
+
+	return files
+}
+
+func headerPath(header []string) string {
+	var path string
+	for _, line := range header {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			if _, b, ok := strings.Cut(line, " b/"); ok {
+				path = b
+			}
+		case strings.HasPrefix(line, "+++ b/"):
+			return strings.TrimPrefix(line, "+++ b/")
+		case strings.HasPrefix(line, "--- a/"):
+			path = strings.TrimPrefix(line, "--- a/")
+		}
+	}
+	return path
+}
+
+func isWhole(file File) bool {
+	if len(file.Hunks) <= 1 {
+		return true
+	}
+
+	for _, line := range file.Header {
+		for _, prefix := range []string{"new file mode", "deleted file mode", "old mode", "rename from", "copy from", "Binary files", "GIT binary patch"} {
+			if strings.HasPrefix(line, prefix) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Units lists every unit of files in diff order.
+func Units(files []File) []Unit {
+	var units []Unit
+	for i, file := range files {
+		if file.Whole {
+			units = append(units, Unit{File: i, Hunk: -1})
+			continue
+		}
+		for j := range file.Hunks {
+			units = append(units, Unit{File: i, Hunk: j})
+		}
+	}
+	return units
+}
+
+// Lines returns the diff lines of unit without the file header.
+func (u Unit) Lines(files []File) []string {
+	file := files[u.File]
+	if u.Hunk < 0 {
+		return file.Lines[len(file.Header):]
+	}
+
+	hunk := file.Hunks[u.Hunk]
+	return append([]string{hunk.Header}, hunk.Lines...)
+}
+
+// Label describes unit in one line, e.g. "cmd/root.go @@ -10,6 +10,8 @@".
+func (u Unit) Label(files []File) string {
+	file := files[u.File]
+	if u.Hunk < 0 {
+		return file.Path
+	}
+
+	header := file.Hunks[u.Hunk].Header
+	if end := strings.Index(header[2:], "@@"); end >= 0 {
+		header = header[:end+4]
+	}
+	return file.Path + " " + header
+}
+
+// Build assembles a patch that git apply accepts from units, keeping files
+// and hunks in diff order.
+func Build(files []File, units []Unit) string {
+	selected := make(map[Unit]bool, len(units))
+	for _, unit := range units {
+		selected[unit] = true
+	}
+
+	var b strings.Builder
+	for i, file := range files {
+		if file.Whole {
+			if selected[Unit{File: i, Hunk: -1}] {
+				writeLines(&b, file.Lines)
+			}
+			continue
+		}
+
+		var hunks []Hunk
+		for j, hunk := range file.Hunks {
+			if selected[Unit{File: i, Hunk: j}] {
+				hunks = append(hunks, hunk)
+			}
+		}
+		if len(hunks) == 0 {
+			continue
+		}
+
+		writeLines(&b, file.Header)
+		for _, hunk := range hunks {
+			b.WriteString(hunk.Header + "\n")
+			writeLines(&b, hunk.Lines)
+		}
+	}
+
+	return b.String()
+}
+
+func writeLines(b *strings.Builder, lines []string) {
+	for _, line := range lines {
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+}
@@ -0,0 +1,76 @@
+package patch
+
+import (
+	"slices"
+	"testing"
+)
+
+const diff = `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -1,3 +1,3 @@ package main
+ a
+-b
++B
+ c
+@@ -20,3 +20,4 @@ func main() {
+ x
+ y
++z
+ w
+diff --git a/README.md b/README.md
+new file mode 100644
+index 0000000..3333333
+--- /dev/null
++++ b/README.md
+@@ -0,0 +1 @@
++# readme
+`
+
+func TestParse(t *testing.T) {
+	files := Parse(diff)
+
+	if len(files) != 2 {
+		t.Fatalf("Parse() returned %d files, want 2", len(files))
+	}
+
+	if files[0].Path != "main.go" || files[0].Whole || len(files[0].Hunks) != 2 {
+		t.Errorf("files[0] = %q whole=%v hunks=%d, want main.go with 2 hunks", files[0].Path, files[0].Whole, len(files[0].Hunks))
+	}
+
+	if files[1].Path != "README.md" || !files[1].Whole {
+		t.Errorf("files[1] = %q whole=%v, want README.md as a whole", files[1].Path, files[1].Whole)
+	}
+
+	want := []Unit{{0, 0}, {0, 1}, {1, -1}}
+	if got := Units(files); !slices.Equal(got, want) {
+		t.Errorf("Units() = %v, want %v", got, want)
+	}
+
+	if got := (Unit{0, 1}).Label(files); got != "main.go @@ -20,3 +20,4 @@" {
+		t.Errorf("Label() = %q", got)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	files := Parse(diff)
+
+	if got := Build(files, Units(files)); got != diff {
+		t.Errorf("Build(all units) =\n%s\nwant\n%s", got, diff)
+	}
+
+	want := `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -20,3 +20,4 @@ func main() {
+ x
+ y
++z
+ w
+`
+	if got := Build(files, []Unit{{0, 1}}); got != want {
+		t.Errorf("Build(second hunk) =\n%s\nwant\n%s", got, want)
+	}
+}
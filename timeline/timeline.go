@@ -11,14 +11,48 @@ import (
 	"github.com/dinoDanic/diny/git"
 	"github.com/dinoDanic/diny/groq"
 	"github.com/dinoDanic/diny/ui"
+	"github.com/spf13/cobra"
 )
 
-func Main() {
-	fmt.Println()
+// Main analyzes the commits of a period. --date or --from and --to pick it
+// without the menu; without a terminal the default is today.
+func Main(cmd *cobra.Command, args []string) {
+	selectedDate, _ := cmd.Flags().GetString("date")
+	startDate, _ := cmd.Flags().GetString("from")
+	endDate, _ := cmd.Flags().GetString("to")
+
+	for _, date := range []string{selectedDate, startDate, endDate} {
+		if date == "" {
+			continue
+		}
+		if err := validateDate(date); err != nil {
+			ui.RenderError(fmt.Sprintf("Invalid date %q, use DD MM YYYY", date))
+			os.Exit(1)
+		}
+	}
 
-	// Show date selection menu
-	choice := timelinePrompt("Choose timeline for commit analysis:")
-	fmt.Println()
+	if (startDate == "") != (endDate == "") {
+		ui.RenderError("--from and --to must be used together")
+		os.Exit(1)
+	}
+
+	var choice string
+	switch {
+	case selectedDate != "" && startDate != "":
+		ui.RenderError("use either --date or --from and --to")
+		os.Exit(1)
+	case selectedDate != "":
+		choice = "date"
+	case startDate != "":
+		choice = "range"
+	case !ui.IsInteractive():
+		choice = "today"
+	default:
+		// Show date selection menu
+		choice = timelinePrompt("Choose timeline for commit analysis:")
+		// Separates the answered menu from the analysis
+		fmt.Println()
+	}
 
 	var timelineCommits []string
 	var dateRange string
@@ -30,13 +64,17 @@ func Main() {
 		timelineCommits, err = git.GetCommitsToday()
 		dateRange = "today"
 	case "date":
-		selectedDate := dateInputPrompt("Enter date (DD MM YYYY):")
+		if selectedDate == "" {
+			selectedDate = dateInputPrompt("Enter date (DD MM YYYY):")
+		}
 		ui.RenderTitle(fmt.Sprintf("Analyzing commits from %s...", selectedDate))
 		timelineCommits, err = git.GetCommitsByDate(selectedDate)
 		dateRange = selectedDate
 	case "range":
-		startDate := dateInputPrompt("Enter start date (DD MM YYYY):")
-		endDate := dateInputPrompt("Enter end date (DD MM YYYY):")
+		if startDate == "" {
+			startDate = dateInputPrompt("Enter start date (DD MM YYYY):")
+			endDate = dateInputPrompt("Enter end date (DD MM YYYY):")
+		}
 		ui.RenderTitle(fmt.Sprintf("Analyzing commits from %s to %s...", startDate, endDate))
 		timelineCommits, err = git.GetCommitsByDateRange(startDate+" 00:00:00", endDate+" 23:59:59")
 		dateRange = fmt.Sprintf("%s to %s", startDate, endDate)
@@ -52,8 +90,8 @@ func Main() {
 		return
 	}
 
+	// RenderTitle ends with the blank line separating it from the box
 	ui.RenderTitle(fmt.Sprintf("Found %d commits from %s", len(timelineCommits), dateRange))
-	fmt.Println()
 
 	// Display the commit messages in a box
 	commitList := ""
@@ -110,13 +148,7 @@ func dateInputPrompt(message string) string {
 		Title("🦕 " + message).
 		Description("Use format: DD MM YYYY (e.g., 15 01 2025)").
 		Placeholder("15 01 2025").
-		Validate(func(s string) error {
-			_, err := time.Parse("02 01 2006", s)
-			if err != nil {
-				return fmt.Errorf("invalid date format, use DD MM YYYY")
-			}
-			return nil
-		}).
+		Validate(validateDate).
 		Value(&input).
 		Run()
 
@@ -127,3 +159,10 @@ func dateInputPrompt(message string) string {
 
 	return input
 }
+
+func validateDate(s string) error {
+	if _, err := time.Parse("02 01 2006", s); err != nil {
+		return fmt.Errorf("invalid date format, use DD MM YYYY")
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package ui
+
+import (
+	"os"
+
+	"github.com/mattn/go-isatty"
+)
+
+var interactiveOverride *bool
+
+// SetInteractive forces interactive mode on or off, overriding terminal detection.
+func SetInteractive(interactive bool) {
+	interactiveOverride = &interactive
+}
+
+// IsInteractive reports whether diny may show prompts. Unless overridden, it
+// requires both stdin and stdout to be terminals.
+func IsInteractive() bool {
+	if interactiveOverride != nil {
+		return *interactiveOverride
+	}
+
+	return isTerminal(os.Stdin) && isTerminal(os.Stdout)
+}
+
+func isTerminal(f *os.File) bool {
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/charmbracelet/huh/spinner"
@@ -68,12 +69,14 @@ func RenderTitle(text string) {
 	fmt.Println()
 }
 
+// RenderError and RenderWarning write to stderr, so stdout only carries
+// results when it is piped.
 func RenderError(text string) {
-	fmt.Println(errorBoxStyle.Render(strings.TrimSpace(text)))
+	fmt.Fprintln(os.Stderr, errorBoxStyle.Render(strings.TrimSpace(text)))
 }
 
 func RenderWarning(text string) {
-	fmt.Println(warningBoxStyle.Render(strings.TrimSpace(text)))
+	fmt.Fprintln(os.Stderr, warningBoxStyle.Render(strings.TrimSpace(text)))
 }
 
 func RenderSuccess(text string) {
@@ -86,7 +89,13 @@ func RenderBox(title, content string) {
 	))
 }
 
+// WithSpinner runs fn behind a spinner, or directly when diny is not
+// interactive.
 func WithSpinner(message string, fn func() error) error {
+	if !IsInteractive() {
+		return fn()
+	}
+
 	var actionErr error
 
 	err := spinner.New().